@@ -12,13 +12,17 @@ package sonickit
 import "C"
 import (
 	"errors"
+	"fmt"
+	"math"
 	"runtime"
 	"unsafe"
 )
 
 // AudioBuffer provides a ring buffer for audio samples.
 type AudioBuffer struct {
-	handle unsafe.Pointer
+	handle  unsafe.Pointer
+	loop    []int16 // loop region with its seam pre-crossfaded
+	loopPos int
 }
 
 // NewAudioBuffer creates a new audio ring buffer.
@@ -26,9 +30,10 @@ type AudioBuffer struct {
 // Parameters:
 //   - capacity: Maximum number of samples the buffer can hold
 func NewAudioBuffer(capacity int) (*AudioBuffer, error) {
+	clearError()
 	handle := C.voice_buffer_create(C.int(capacity))
 	if handle == nil {
-		return nil, errors.New("failed to create audio buffer")
+		return nil, createError("audio buffer")
 	}
 	b := &AudioBuffer{handle: handle}
 	runtime.SetFinalizer(b, (*AudioBuffer).Close)
@@ -82,6 +87,59 @@ func (b *AudioBuffer) Clear() {
 	}
 }
 
+// loopCrossfadeSamples is the length of the crossfade applied at the loop
+// seam by SetLoop.
+const loopCrossfadeSamples = 64
+
+// SetLoop enters loop mode over length samples starting start samples past
+// the current read position, for use with ReadLoop. The region is captured
+// when SetLoop is called and nothing is consumed from the buffer.
+//
+// To avoid a click at the seam, the end of the region is crossfaded into
+// its start over up to 64 samples (at most half the region), so one loop
+// cycle is that much shorter than length. A length of zero, or a region
+// extending past the buffered samples, leaves loop mode.
+func (b *AudioBuffer) SetLoop(start, length int) {
+	b.loop, b.loopPos = nil, 0
+	if b.handle == nil || start < 0 || length < 2 || start+length > b.Available() {
+		return
+	}
+	region := make([]int16, length)
+	n := C.voice_buffer_peek(b.handle,
+		(*C.short)(unsafe.Pointer(&region[0])),
+		C.int(start), C.int(length))
+	if int(n) != length {
+		return
+	}
+
+	fade := loopCrossfadeSamples
+	if fade > length/2 {
+		fade = length / 2
+	}
+	period := length - fade
+	for i := 0; i < fade; i++ {
+		t := (float64(i) + 0.5) / float64(fade)
+		region[i] = clampInt16(float64(region[i])*t + float64(region[period+i])*(1-t))
+	}
+	b.loop = region[:period]
+}
+
+// ReadLoop returns the next numSamples samples of the loop region set by
+// SetLoop, wrapping around as often as needed. It does not consume samples
+// from the buffer, and returns nil when loop mode is not active.
+func (b *AudioBuffer) ReadLoop(numSamples int) []int16 {
+	if len(b.loop) == 0 || numSamples <= 0 {
+		return nil
+	}
+	output := make([]int16, numSamples)
+	for filled := 0; filled < numSamples; {
+		n := copy(output[filled:], b.loop[b.loopPos:])
+		filled += n
+		b.loopPos = (b.loopPos + n) % len(b.loop)
+	}
+	return output
+}
+
 // Close releases the buffer resources.
 func (b *AudioBuffer) Close() error {
 	if b.handle != nil {
@@ -94,20 +152,33 @@ func (b *AudioBuffer) Close() error {
 
 // AudioLevel provides audio level metering.
 type AudioLevel struct {
-	handle unsafe.Pointer
+	handle        unsafe.Pointer
+	sampleRate    int
+	clipThreshold int // absolute sample value counted as clipping
+	clipCount     int
+	clipCallback  func(count int)
 }
 
 // NewAudioLevel creates a new audio level meter.
 func NewAudioLevel(sampleRate, windowMs int) (*AudioLevel, error) {
+	if err := validate("audio level meter", checkSampleRate(sampleRate)); err != nil {
+		return nil, err
+	}
+	clearError()
 	handle := C.voice_level_create(C.int(sampleRate), C.int(windowMs))
 	if handle == nil {
-		return nil, errors.New("failed to create audio level meter")
+		return nil, createError("audio level meter")
 	}
-	l := &AudioLevel{handle: handle}
+	l := &AudioLevel{handle: handle, sampleRate: sampleRate, clipThreshold: math.MaxInt16}
 	runtime.SetFinalizer(l, (*AudioLevel).Close)
 	return l, nil
 }
 
+// SampleRate returns the sample rate in Hz.
+func (l *AudioLevel) SampleRate() int {
+	return l.sampleRate
+}
+
 // Process processes audio and updates level measurement.
 func (l *AudioLevel) Process(input []int16) {
 	if l.handle == nil || len(input) == 0 {
@@ -116,6 +187,47 @@ func (l *AudioLevel) Process(input []int16) {
 	C.voice_level_process(l.handle,
 		(*C.short)(unsafe.Pointer(&input[0])),
 		C.int(len(input)))
+
+	clipped := 0
+	for _, s := range input {
+		if int(s) >= l.clipThreshold || -int(s) >= l.clipThreshold {
+			clipped++
+		}
+	}
+	if clipped > 0 {
+		l.clipCount += clipped
+		if l.clipCallback != nil {
+			l.clipCallback(l.clipCount)
+		}
+	}
+}
+
+// SetClipThreshold sets the level in dBFS at or above which a sample is
+// counted as clipped. The default is 0 dBFS (full scale).
+func (l *AudioLevel) SetClipThreshold(dbfs float32) {
+	threshold := int(math.Round(math.MaxInt16 * math.Pow(10, float64(dbfs)/20)))
+	if threshold < 1 {
+		threshold = 1
+	}
+	l.clipThreshold = threshold
+}
+
+// ClipCount returns the number of clipped samples since the last
+// ResetClipCount.
+func (l *AudioLevel) ClipCount() int {
+	return l.clipCount
+}
+
+// ResetClipCount resets the clipped sample count to zero.
+func (l *AudioLevel) ResetClipCount() {
+	l.clipCount = 0
+}
+
+// SetClipCallback registers fn to be called with the running ClipCount
+// whenever Process detects new clipping. fn runs synchronously on the
+// goroutine calling Process. Pass nil to remove the callback.
+func (l *AudioLevel) SetClipCallback(fn func(count int)) {
+	l.clipCallback = fn
 }
 
 // GetRMS returns the current RMS level in dBFS.
@@ -144,10 +256,151 @@ func (l *AudioLevel) Close() error {
 	return nil
 }
 
+// AudioLevelMulti meters each channel of interleaved multi-channel audio
+// separately, so e.g. the clipping channel of a stereo stream can be
+// identified.
+type AudioLevelMulti struct {
+	meters     []*AudioLevel
+	sampleRate int
+	scratch    []int16
+
+	// Stereo correlation window (2-channel mode only)
+	corrL, corrR []int16
+	corrPos      int
+	corrFilled   int
+}
+
+// NewAudioLevelMulti creates a level meter for interleaved audio with the
+// given number of channels.
+//
+// Parameters:
+//   - sampleRate: Audio sample rate in Hz
+//   - windowMs: Measurement window in milliseconds
+//   - channels: Number of interleaved channels
+func NewAudioLevelMulti(sampleRate, windowMs, channels int) (*AudioLevelMulti, error) {
+	if err := validate("audio level meter", checkChannels(channels)); err != nil {
+		return nil, err
+	}
+	m := &AudioLevelMulti{sampleRate: sampleRate, meters: make([]*AudioLevel, channels)}
+	for ch := range m.meters {
+		level, err := NewAudioLevel(sampleRate, windowMs)
+		if err != nil {
+			m.Close()
+			return nil, err
+		}
+		m.meters[ch] = level
+	}
+	if channels == 2 {
+		window := sampleRate * windowMs / 1000
+		if window < 1 {
+			window = 1
+		}
+		m.corrL = make([]int16, window)
+		m.corrR = make([]int16, window)
+	}
+	return m, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (m *AudioLevelMulti) SampleRate() int {
+	return m.sampleRate
+}
+
+// Channels returns the number of channels being metered.
+func (m *AudioLevelMulti) Channels() int {
+	return len(m.meters)
+}
+
+// ProcessInterleaved updates the per-channel levels from interleaved
+// audio. len(input) must be a multiple of Channels().
+func (m *AudioLevelMulti) ProcessInterleaved(input []int16) error {
+	channels := len(m.meters)
+	if channels == 0 {
+		return errors.New("level meter is closed")
+	}
+	if len(input)%channels != 0 {
+		return fmt.Errorf("%d samples is not a multiple of %d channels", len(input), channels)
+	}
+	frames := len(input) / channels
+	if cap(m.scratch) < frames {
+		m.scratch = make([]int16, frames)
+	}
+	plane := m.scratch[:frames]
+	for ch, meter := range m.meters {
+		for i := range plane {
+			plane[i] = input[i*channels+ch]
+		}
+		meter.Process(plane)
+	}
+	if channels == 2 {
+		for i := 0; i < frames; i++ {
+			m.corrL[m.corrPos] = input[2*i]
+			m.corrR[m.corrPos] = input[2*i+1]
+			m.corrPos = (m.corrPos + 1) % len(m.corrL)
+		}
+		m.corrFilled += frames
+		if m.corrFilled > len(m.corrL) {
+			m.corrFilled = len(m.corrL)
+		}
+	}
+	return nil
+}
+
+// GetCorrelation returns the phase correlation of the left and right
+// channels over the measurement window, from -1 (fully out of phase, mono
+// sum cancels) through 0 (uncorrelated) to +1 (identical, mono
+// compatible). It requires 2-channel mode and returns 0 otherwise, or
+// while either channel is silent.
+func (m *AudioLevelMulti) GetCorrelation() float32 {
+	if len(m.meters) != 2 || m.corrFilled == 0 {
+		return 0
+	}
+	var lr, ll, rr float64
+	for i := 0; i < m.corrFilled; i++ {
+		l, r := float64(m.corrL[i]), float64(m.corrR[i])
+		lr += l * r
+		ll += l * l
+		rr += r * r
+	}
+	if ll == 0 || rr == 0 {
+		return 0
+	}
+	return float32(lr / math.Sqrt(ll*rr))
+}
+
+// GetRMSChannel returns the current RMS level of a channel in dBFS.
+func (m *AudioLevelMulti) GetRMSChannel(ch int) float32 {
+	if ch < 0 || ch >= len(m.meters) {
+		return -100
+	}
+	return m.meters[ch].GetRMS()
+}
+
+// GetPeakChannel returns the current peak level of a channel in dBFS.
+func (m *AudioLevelMulti) GetPeakChannel(ch int) float32 {
+	if ch < 0 || ch >= len(m.meters) {
+		return -100
+	}
+	return m.meters[ch].GetPeak()
+}
+
+// Close releases the per-channel meters.
+func (m *AudioLevelMulti) Close() error {
+	for _, meter := range m.meters {
+		if meter != nil {
+			meter.Close()
+		}
+	}
+	m.meters = nil
+	return nil
+}
+
 // AudioMixer provides multi-channel audio mixing.
 type AudioMixer struct {
-	handle   unsafe.Pointer
-	channels int
+	handle    unsafe.Pointer
+	frameSize int
+	channels  int
+	delays    []*sampleDelay // per-channel alignment delay, nil if none
 }
 
 // NewAudioMixer creates a new audio mixer.
@@ -156,15 +409,24 @@ type AudioMixer struct {
 //   - channels: Maximum number of input channels
 //   - frameSize: Samples per frame
 func NewAudioMixer(channels, frameSize int) (*AudioMixer, error) {
+	if err := validate("audio mixer", checkChannels(channels), checkFrameSize(frameSize)); err != nil {
+		return nil, err
+	}
+	clearError()
 	handle := C.voice_mixer_create(C.int(channels), C.int(frameSize))
 	if handle == nil {
-		return nil, errors.New("failed to create audio mixer")
+		return nil, createError("audio mixer")
 	}
-	m := &AudioMixer{handle: handle, channels: channels}
+	m := &AudioMixer{handle: handle, channels: channels, frameSize: frameSize, delays: make([]*sampleDelay, channels)}
 	runtime.SetFinalizer(m, (*AudioMixer).Close)
 	return m, nil
 }
 
+// FrameSize returns the number of samples per frame.
+func (m *AudioMixer) FrameSize() int {
+	return m.frameSize
+}
+
 // SetChannelGain sets the gain for a specific channel.
 func (m *AudioMixer) SetChannelGain(channel int, gain float32) {
 	if m.handle != nil && channel >= 0 && channel < m.channels {
@@ -172,17 +434,63 @@ func (m *AudioMixer) SetChannelGain(channel int, gain float32) {
 	}
 }
 
+// SetMute mutes or unmutes a channel. Muting is independent of the
+// channel gain, which is restored when the channel is unmuted.
+func (m *AudioMixer) SetMute(channel int, muted bool) {
+	if m.handle == nil || channel < 0 || channel >= m.channels {
+		return
+	}
+	var flag C.int
+	if muted {
+		flag = 1
+	}
+	C.voice_mixer_set_mute(m.handle, C.int(channel), flag)
+}
+
+// SetSolo solos or unsolos a channel. While any channel is soloed, every
+// channel that is not soloed is left out of the mix. Like SetMute, this
+// does not change the channel gain.
+func (m *AudioMixer) SetSolo(channel int, solo bool) {
+	if m.handle == nil || channel < 0 || channel >= m.channels {
+		return
+	}
+	var flag C.int
+	if solo {
+		flag = 1
+	}
+	C.voice_mixer_set_solo(m.handle, C.int(channel), flag)
+}
+
+// SetChannelDelay delays a channel by the given number of samples before
+// it is mixed, e.g. to align a dry path with a processed path that adds
+// latency (see LatencyReporter) for parallel compression. Changing the
+// delay restarts the channel's delay line from silence; 0 removes it.
+func (m *AudioMixer) SetChannelDelay(channel int, samples int) {
+	if channel < 0 || channel >= m.channels || samples < 0 {
+		return
+	}
+	if samples == 0 {
+		m.delays[channel] = nil
+		return
+	}
+	m.delays[channel] = &sampleDelay{line: make([]int16, samples)}
+}
+
 // AddChannel adds audio from a channel to the mix.
 func (m *AudioMixer) AddChannel(channel int, input []int16) {
 	if m.handle == nil || channel < 0 || channel >= m.channels || len(input) == 0 {
 		return
 	}
+	if d := m.delays[channel]; d != nil {
+		input = d.process(input)
+	}
 	C.voice_mixer_add(m.handle, C.int(channel),
 		(*C.short)(unsafe.Pointer(&input[0])),
 		C.int(len(input)))
 }
 
 // Mix returns the mixed output and clears internal buffers.
+// It is equivalent to MixPeek followed by ClearChannels.
 func (m *AudioMixer) Mix(frameSize int) []int16 {
 	if m.handle == nil || frameSize <= 0 {
 		return nil
@@ -194,6 +502,64 @@ func (m *AudioMixer) Mix(frameSize int) []int16 {
 	return output
 }
 
+// MixPeek returns the mixed output without clearing internal buffers, so
+// the same accumulated channels can be mixed again (for example with
+// different gains for a monitor mix and a main mix). Call ClearChannels, or
+// use Mix for the final mix, before adding the next frame.
+func (m *AudioMixer) MixPeek(frameSize int) []int16 {
+	if m.handle == nil || frameSize <= 0 {
+		return nil
+	}
+	output := make([]int16, frameSize)
+	C.voice_mixer_mix_peek(m.handle,
+		(*C.short)(unsafe.Pointer(&output[0])),
+		C.int(frameSize))
+	return output
+}
+
+// SetMasterLimiter enables or disables a brickwall limiter on the summed
+// output, so that Mix and MixPeek never exceed ceilingDb (dBFS) however
+// many loud channels are added. The limiter reacts instantly to peaks and
+// recovers smoothly, so it is transparent unless the mix runs hot. A
+// ceiling above 0dBFS is treated as 0dBFS. The limiter is off by default.
+func (m *AudioMixer) SetMasterLimiter(enabled bool, ceilingDb float32) {
+	if m.handle == nil {
+		return
+	}
+	var flag C.int
+	if enabled {
+		flag = 1
+	}
+	C.voice_mixer_set_limiter(m.handle, flag, C.float(min(ceilingDb, 0)))
+}
+
+// MixStats holds AudioMixer output statistics, accumulated since the mixer
+// was created.
+type MixStats struct {
+	FramesMixed int     // Frames produced by Mix and MixPeek
+	PeakDb      float32 // Highest output peak in dBFS, before clipping
+	ClipCount   int     // Output samples that exceeded full scale and were clipped
+}
+
+// Stats returns the output statistics. A nonzero ClipCount means the mix
+// is too hot; lower the channel gains or enable SetMasterLimiter.
+func (m *AudioMixer) Stats() MixStats {
+	if m.handle == nil {
+		return MixStats{}
+	}
+	var frames, clipped C.int
+	var peak C.float
+	C.voice_mixer_get_stats(m.handle, &frames, &peak, &clipped)
+	return MixStats{FramesMixed: int(frames), PeakDb: float32(peak), ClipCount: int(clipped)}
+}
+
+// ClearChannels discards the audio accumulated by AddChannel.
+func (m *AudioMixer) ClearChannels() {
+	if m.handle != nil {
+		C.voice_mixer_clear(m.handle)
+	}
+}
+
 // Close releases the mixer resources.
 func (m *AudioMixer) Close() error {
 	if m.handle != nil {
@@ -204,9 +570,31 @@ func (m *AudioMixer) Close() error {
 	return nil
 }
 
+// sampleDelay is a fixed delay line.
+type sampleDelay struct {
+	line []int16
+	pos  int
+}
+
+// process returns input delayed by len(d.line) samples.
+func (d *sampleDelay) process(input []int16) []int16 {
+	output := make([]int16, len(input))
+	for i, s := range input {
+		output[i] = d.line[d.pos]
+		d.line[d.pos] = s
+		d.pos++
+		if d.pos == len(d.line) {
+			d.pos = 0
+		}
+	}
+	return output
+}
+
 // JitterBuffer provides network jitter compensation.
 type JitterBuffer struct {
-	handle unsafe.Pointer
+	handle     unsafe.Pointer
+	sampleRate int
+	decoder    func(payload []byte) []int16
 }
 
 // NewJitterBuffer creates a new jitter buffer.
@@ -217,16 +605,25 @@ type JitterBuffer struct {
 //   - minDelayMs: Minimum buffering delay in milliseconds
 //   - maxDelayMs: Maximum buffering delay in milliseconds
 func NewJitterBuffer(sampleRate, frameSizeMs, minDelayMs, maxDelayMs int) (*JitterBuffer, error) {
+	if err := validate("jitter buffer", checkSampleRate(sampleRate), checkFrameSize(frameSizeMs)); err != nil {
+		return nil, err
+	}
+	clearError()
 	handle := C.voice_jitter_create(C.int(sampleRate), C.int(frameSizeMs),
 		C.int(minDelayMs), C.int(maxDelayMs))
 	if handle == nil {
-		return nil, errors.New("failed to create jitter buffer")
+		return nil, createError("jitter buffer")
 	}
-	j := &JitterBuffer{handle: handle}
+	j := &JitterBuffer{handle: handle, sampleRate: sampleRate}
 	runtime.SetFinalizer(j, (*JitterBuffer).Close)
 	return j, nil
 }
 
+// SampleRate returns the sample rate in Hz.
+func (j *JitterBuffer) SampleRate() int {
+	return j.sampleRate
+}
+
 // Put adds a packet to the jitter buffer.
 //
 // Parameters:
@@ -264,6 +661,32 @@ func (j *JitterBuffer) GetDelay() int {
 	return int(C.voice_jitter_get_delay(j.handle))
 }
 
+// SetAdaptiveMode enables or disables adaptive playout. When enabled, Get
+// time-scales the audio slightly (WSOLA), stretching to build up delay when
+// packets arrive late and compressing to shed it when they arrive early,
+// so the buffer converges on its target delay without clicks or dropped
+// frames.
+func (j *JitterBuffer) SetAdaptiveMode(enabled bool) {
+	if j.handle == nil {
+		return
+	}
+	var flag C.int
+	if enabled {
+		flag = 1
+	}
+	C.voice_jitter_set_adaptive(j.handle, flag)
+}
+
+// ScalingFactor returns the time-scaling factor applied by the most recent
+// Get in adaptive mode: 1.0 is normal speed, below 1.0 means playout is
+// being compressed to reduce delay and above 1.0 stretched to increase it.
+func (j *JitterBuffer) ScalingFactor() float32 {
+	if j.handle == nil {
+		return 1
+	}
+	return float32(C.voice_jitter_get_scaling(j.handle))
+}
+
 // Close releases the jitter buffer resources.
 func (j *JitterBuffer) Close() error {
 	if j.handle != nil {
@@ -276,20 +699,36 @@ func (j *JitterBuffer) Close() error {
 
 // SpatialRenderer provides 3D spatial audio rendering.
 type SpatialRenderer struct {
-	handle unsafe.Pointer
+	handle     unsafe.Pointer
+	frameSize  int
+	sampleRate int
 }
 
 // NewSpatialRenderer creates a new spatial audio renderer.
 func NewSpatialRenderer(sampleRate, frameSize int) (*SpatialRenderer, error) {
+	if err := validate("spatial renderer", checkSampleRate(sampleRate), checkFrameSize(frameSize)); err != nil {
+		return nil, err
+	}
+	clearError()
 	handle := C.voice_spatial_create(C.int(sampleRate), C.int(frameSize))
 	if handle == nil {
-		return nil, errors.New("failed to create spatial renderer")
+		return nil, createError("spatial renderer")
 	}
-	s := &SpatialRenderer{handle: handle}
+	s := &SpatialRenderer{handle: handle, sampleRate: sampleRate, frameSize: frameSize}
 	runtime.SetFinalizer(s, (*SpatialRenderer).Close)
 	return s, nil
 }
 
+// SampleRate returns the sample rate in Hz.
+func (s *SpatialRenderer) SampleRate() int {
+	return s.sampleRate
+}
+
+// FrameSize returns the number of samples per frame.
+func (s *SpatialRenderer) FrameSize() int {
+	return s.frameSize
+}
+
 // SetSourcePosition sets the audio source position in 3D space.
 func (s *SpatialRenderer) SetSourcePosition(x, y, z float32) {
 	if s.handle != nil {
@@ -330,20 +769,30 @@ func (s *SpatialRenderer) Close() error {
 
 // Hrtf provides head-related transfer function processing.
 type Hrtf struct {
-	handle unsafe.Pointer
+	handle     unsafe.Pointer
+	sampleRate int
 }
 
 // NewHrtf creates a new HRTF processor.
 func NewHrtf(sampleRate int) (*Hrtf, error) {
+	if err := validate("HRTF processor", checkSampleRate(sampleRate)); err != nil {
+		return nil, err
+	}
+	clearError()
 	handle := C.voice_hrtf_create(C.int(sampleRate))
 	if handle == nil {
-		return nil, errors.New("failed to create HRTF processor")
+		return nil, createError("HRTF processor")
 	}
-	h := &Hrtf{handle: handle}
+	h := &Hrtf{handle: handle, sampleRate: sampleRate}
 	runtime.SetFinalizer(h, (*Hrtf).Close)
 	return h, nil
 }
 
+// SampleRate returns the sample rate in Hz.
+func (h *Hrtf) SampleRate() int {
+	return h.sampleRate
+}
+
 // SetAzimuth sets the horizontal angle in degrees (-180 to 180).
 func (h *Hrtf) SetAzimuth(azimuth float32) {
 	if h.handle != nil {
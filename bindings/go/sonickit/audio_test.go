@@ -1,6 +1,9 @@
 package sonickit
 
 import (
+	"bytes"
+	"io"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -36,6 +39,51 @@ func TestAudioBuffer(t *testing.T) {
 	assert.Equal(t, 0, buffer.Available())
 }
 
+func TestAudioBufferLoop(t *testing.T) {
+	buffer, err := NewAudioBuffer(4096)
+	require.NoError(t, err)
+	defer buffer.Close()
+
+	// 1009 samples is not a whole number of cycles, so a hard wrap would
+	// jump by nearly full scale
+	tone := make([]int16, 2000)
+	for i := range tone {
+		tone[i] = int16(10000 * math.Sin(2*math.Pi*440*float64(i)/16000))
+	}
+	buffer.Write(tone)
+
+	buffer.SetLoop(0, 1009)
+	output := buffer.ReadLoop(5000)
+	require.Len(t, output, 5000)
+	assert.Equal(t, 2000, buffer.Available(), "looping must not consume")
+
+	period := 1009 - loopCrossfadeSamples
+	for i := 0; i+period < len(output); i++ {
+		require.Equal(t, output[i], output[i+period], "sample %d", i)
+	}
+	maxDelta := 0
+	for i := 1; i < len(output); i++ {
+		d := int(output[i]) - int(output[i-1])
+		if d < 0 {
+			d = -d
+		}
+		if d > maxDelta {
+			maxDelta = d
+		}
+	}
+	// A 440Hz tone at this level moves at most ~1730 per sample
+	assert.Less(t, maxDelta, 2500)
+
+	// Reads continue where the previous one stopped
+	next := buffer.ReadLoop(10)
+	assert.Equal(t, output[5000%period:5000%period+10], next)
+
+	buffer.SetLoop(0, 0)
+	assert.Nil(t, buffer.ReadLoop(10))
+	buffer.SetLoop(1500, 1000)
+	assert.Nil(t, buffer.ReadLoop(10), "region past the buffered samples")
+}
+
 func TestAudioLevel(t *testing.T) {
 	level, err := NewAudioLevel(16000, 20)
 	require.NoError(t, err)
@@ -60,6 +108,92 @@ func TestAudioLevel(t *testing.T) {
 	assert.Greater(t, rms, float32(-100))
 }
 
+func TestAudioLevelClipDetection(t *testing.T) {
+	level, err := NewAudioLevel(16000, 20)
+	require.NoError(t, err)
+	defer level.Close()
+
+	var reported []int
+	level.SetClipCallback(func(count int) {
+		reported = append(reported, count)
+	})
+
+	// Quiet audio does not clip
+	quiet := make([]int16, 320)
+	level.Process(quiet)
+	assert.Equal(t, 0, level.ClipCount())
+	assert.Empty(t, reported)
+
+	// Full-scale samples clip
+	loud := make([]int16, 320)
+	for i := range loud {
+		if i%2 == 0 {
+			loud[i] = 32767
+		} else {
+			loud[i] = -32768
+		}
+	}
+	level.Process(loud)
+	assert.Equal(t, 320, level.ClipCount())
+	assert.Equal(t, []int{320}, reported)
+
+	// Lowering the threshold counts near-full-scale samples too
+	level.ResetClipCount()
+	level.SetClipThreshold(-6)
+	for i := range quiet {
+		quiet[i] = 20000
+	}
+	level.Process(quiet)
+	assert.Equal(t, 320, level.ClipCount())
+}
+
+func TestAudioLevelMulti(t *testing.T) {
+	level, err := NewAudioLevelMulti(16000, 20, 2)
+	require.NoError(t, err)
+	defer level.Close()
+	assert.Equal(t, 2, level.Channels())
+
+	// Left channel 12dB louder than right
+	stereo := make([]int16, 2*320)
+	for i := 0; i < 320; i++ {
+		v := math.Sin(2 * math.Pi * 1000 * float64(i) / 16000)
+		stereo[2*i] = int16(16000 * v)
+		stereo[2*i+1] = int16(4000 * v)
+	}
+	require.NoError(t, level.ProcessInterleaved(stereo))
+
+	assert.Greater(t, level.GetPeakChannel(0), level.GetPeakChannel(1))
+	assert.Greater(t, level.GetRMSChannel(0), level.GetRMSChannel(1))
+	assert.Equal(t, float32(-100), level.GetPeakChannel(2))
+
+	assert.Error(t, level.ProcessInterleaved(stereo[:3]))
+}
+
+func TestAudioLevelMultiCorrelation(t *testing.T) {
+	measure := func(rightSign float64) float32 {
+		level, err := NewAudioLevelMulti(16000, 20, 2)
+		require.NoError(t, err)
+		defer level.Close()
+
+		stereo := make([]int16, 2*640)
+		for i := 0; i < 640; i++ {
+			v := 8000 * math.Sin(2*math.Pi*440*float64(i)/16000)
+			stereo[2*i] = int16(v)
+			stereo[2*i+1] = int16(rightSign * v)
+		}
+		require.NoError(t, level.ProcessInterleaved(stereo))
+		return level.GetCorrelation()
+	}
+
+	assert.InDelta(t, 1, measure(1), 0.01)
+	assert.InDelta(t, -1, measure(-1), 0.01)
+
+	mono, err := NewAudioLevelMulti(16000, 20, 1)
+	require.NoError(t, err)
+	defer mono.Close()
+	assert.Equal(t, float32(0), mono.GetCorrelation())
+}
+
 func TestAudioMixer(t *testing.T) {
 	mixer, err := NewAudioMixer(4, 160)
 	require.NoError(t, err)
@@ -85,6 +219,150 @@ func TestAudioMixer(t *testing.T) {
 	assert.Len(t, output, 160)
 }
 
+func TestAudioMixerMixPeek(t *testing.T) {
+	mixer, err := NewAudioMixer(2, 160)
+	require.NoError(t, err)
+	defer mixer.Close()
+
+	ch0 := make([]int16, 160)
+	ch1 := make([]int16, 160)
+	for i := range ch0 {
+		ch0[i] = 1000
+		ch1[i] = 2000
+	}
+	mixer.AddChannel(0, ch0)
+	mixer.AddChannel(1, ch1)
+
+	// Peeking leaves the accumulated channels in place
+	first := mixer.MixPeek(160)
+	second := mixer.MixPeek(160)
+	require.Len(t, first, 160)
+	assert.Equal(t, first, second)
+	assert.NotZero(t, first[0])
+
+	// Mix returns the same result and then clears
+	assert.Equal(t, first, mixer.Mix(160))
+	for _, s := range mixer.MixPeek(160) {
+		assert.Zero(t, s)
+	}
+
+	mixer.AddChannel(0, ch0)
+	mixer.ClearChannels()
+	for _, s := range mixer.Mix(160) {
+		assert.Zero(t, s)
+	}
+}
+
+func TestAudioMixerSolo(t *testing.T) {
+	mixer, err := NewAudioMixer(3, 160)
+	require.NoError(t, err)
+	defer mixer.Close()
+
+	channels := [][]int16{make([]int16, 160), make([]int16, 160), make([]int16, 160)}
+	for ch, buf := range channels {
+		for i := range buf {
+			buf[i] = int16(1000 * (ch + 1))
+		}
+	}
+	addAll := func() {
+		for ch, buf := range channels {
+			mixer.AddChannel(ch, buf)
+		}
+	}
+
+	addAll()
+	soloRef := mixer.Mix(160)
+
+	// Soloing channel 1 leaves only its signal in the mix
+	mixer.SetSolo(1, true)
+	addAll()
+	soloed := mixer.Mix(160)
+	require.Len(t, soloed, 160)
+	assert.Equal(t, channels[1][0], soloed[0])
+	assert.NotEqual(t, soloRef[0], soloed[0])
+
+	// Mute still applies to a soloed channel
+	mixer.SetMute(1, true)
+	addAll()
+	assert.Zero(t, mixer.Mix(160)[0])
+
+	// Clearing solo and mute restores the full mix
+	mixer.SetSolo(1, false)
+	mixer.SetMute(1, false)
+	addAll()
+	assert.Equal(t, soloRef, mixer.Mix(160))
+}
+
+func TestAudioMixerChannelDelay(t *testing.T) {
+	mixer, err := NewAudioMixer(2, 160)
+	require.NoError(t, err)
+	defer mixer.Close()
+
+	// Channel 1 carries the same impulse 37 samples late; delaying
+	// channel 0 by 37 lines the two up
+	const lag = 37
+	mixer.SetChannelDelay(0, lag)
+
+	var output []int16
+	for frame := 0; frame < 2; frame++ {
+		ch0 := make([]int16, 160)
+		ch1 := make([]int16, 160)
+		if frame == 0 {
+			ch0[10] = 8000
+			ch1[10+lag] = 8000
+		}
+		mixer.AddChannel(0, ch0)
+		mixer.AddChannel(1, ch1)
+		output = append(output, mixer.Mix(160)...)
+	}
+
+	peak := 0
+	for i, s := range output {
+		if s > output[peak] {
+			peak = i
+		}
+	}
+	assert.Equal(t, 10+lag, peak)
+	assert.Zero(t, output[10])
+
+	d := &sampleDelay{line: make([]int16, 3)}
+	assert.Equal(t, []int16{0, 0, 0, 1, 2}, d.process([]int16{1, 2, 3, 4, 5}))
+	assert.Equal(t, []int16{3, 4, 5}, d.process([]int16{6, 7, 8}))
+}
+
+func TestAudioMixerMasterLimiter(t *testing.T) {
+	mix := func(limit bool) ([]int16, MixStats) {
+		mixer, err := NewAudioMixer(2, 160)
+		require.NoError(t, err)
+		defer mixer.Close()
+		mixer.SetMasterLimiter(limit, -1)
+
+		// Two full-scale tones in phase sum to twice full scale
+		tone := Sine(16000, 440, 1, 1600)
+		var output []int16
+		for i := 0; i < len(tone); i += 160 {
+			mixer.AddChannel(0, tone[i:i+160])
+			mixer.AddChannel(1, tone[i:i+160])
+			output = append(output, mixer.Mix(160)...)
+		}
+		return output, mixer.Stats()
+	}
+
+	_, stats := mix(false)
+	assert.Equal(t, 10, stats.FramesMixed)
+	assert.Positive(t, stats.ClipCount)
+	assert.Greater(t, stats.PeakDb, float32(5))
+
+	output, stats := mix(true)
+	assert.Zero(t, stats.ClipCount)
+	assert.InDelta(t, -1, stats.PeakDb, 0.1)
+	ceiling := int16(dbToGain(-1) * 32768)
+	for _, s := range output {
+		require.LessOrEqual(t, s, ceiling)
+		require.GreaterOrEqual(t, s, -ceiling)
+	}
+}
+
 func TestJitterBuffer(t *testing.T) {
 	jitter, err := NewJitterBuffer(16000, 20, 40, 200)
 	require.NoError(t, err)
@@ -107,6 +385,40 @@ func TestJitterBuffer(t *testing.T) {
 	t.Logf("Jitter buffer delay: %d ms", delay)
 }
 
+func TestJitterBufferAdaptive(t *testing.T) {
+	jitter, err := NewJitterBuffer(16000, 20, 40, 400)
+	require.NoError(t, err)
+	defer jitter.Close()
+	jitter.SetAdaptiveMode(true)
+	assert.Equal(t, float32(1), jitter.ScalingFactor())
+
+	packet := make([]int16, 320)
+	for i := range packet {
+		packet[i] = int16(3000 * math.Sin(2*math.Pi*200*float64(i)/16000))
+	}
+
+	// A burst of early packets builds up excess delay
+	seq := uint16(0)
+	for ; seq < 15; seq++ {
+		jitter.Put(packet, uint32(seq)*320, seq)
+	}
+	jitter.Get(320)
+	initial := jitter.GetDelay()
+
+	// Steady arrival afterwards lets playout compress back toward the target
+	compressed := false
+	for i := 0; i < 100; i++ {
+		jitter.Put(packet, uint32(seq)*320, seq)
+		seq++
+		assert.Len(t, jitter.Get(320), 320)
+		if jitter.ScalingFactor() < 1 {
+			compressed = true
+		}
+	}
+	assert.True(t, compressed)
+	assert.Less(t, jitter.GetDelay(), initial)
+}
+
 func TestSpatialRenderer(t *testing.T) {
 	spatial, err := NewSpatialRenderer(48000, 480)
 	require.NoError(t, err)
@@ -173,3 +485,135 @@ func TestG711Codec(t *testing.T) {
 
 	assert.False(t, ulaw.IsAlaw())
 }
+
+func TestG722Codec(t *testing.T) {
+	codec, err := NewG722Codec()
+	require.NoError(t, err)
+	require.NotNil(t, codec)
+	defer codec.Close()
+
+	// 20ms of a 1kHz tone at 16kHz
+	input := make([]int16, 320)
+	for i := range input {
+		input[i] = int16(8000 * math.Sin(2*math.Pi*1000*float64(i)/16000))
+	}
+
+	encoded := codec.Encode(input)
+	assert.Len(t, encoded, len(input)/2) // 64kbit/s
+
+	decoded := codec.Decode(encoded)
+	require.Len(t, decoded, len(input))
+
+	// The codec delays and colors the signal, so compare energy rather
+	// than individual samples
+	var inEnergy, outEnergy float64
+	for i := range input {
+		inEnergy += float64(input[i]) * float64(input[i])
+		outEnergy += float64(decoded[i]) * float64(decoded[i])
+	}
+	assert.InDelta(t, 1.0, outEnergy/inEnergy, 0.5)
+
+	assert.Nil(t, codec.Encode(nil))
+	assert.Nil(t, codec.Decode(nil))
+}
+
+func TestADPCMCodecBitrate(t *testing.T) {
+	variants := []struct {
+		variant ADPCMVariant
+		kbps    int
+	}{
+		{ADPCMIMA, 32},
+		{ADPCMG726Kbps16, 16},
+		{ADPCMG726Kbps24, 24},
+		{ADPCMG726Kbps32, 32},
+		{ADPCMG726Kbps40, 40},
+	}
+
+	// One second at 8kHz, fed in 20ms frames
+	input := make([]int16, 8000)
+	for i := range input {
+		input[i] = int16(8000 * math.Sin(2*math.Pi*440*float64(i)/8000))
+	}
+
+	for _, v := range variants {
+		codec, err := NewADPCMCodec(v.variant)
+		require.NoError(t, err)
+		assert.Equal(t, v.variant, codec.Variant())
+
+		var encoded []byte
+		for i := 0; i < len(input); i += 160 {
+			encoded = append(encoded, codec.Encode(input[i:i+160])...)
+		}
+		assert.Len(t, encoded, v.kbps*1000/8, "variant %d", v.variant)
+		codec.Close()
+	}
+
+	_, err := NewADPCMCodec(ADPCMVariant(99))
+	assert.Error(t, err)
+}
+
+func TestADPCMCodecStream(t *testing.T) {
+	input := Sine(8000, 440, 0.25, 8000)
+	for _, variant := range []ADPCMVariant{ADPCMIMA, ADPCMG726Kbps24, ADPCMG726Kbps40} {
+		whole, err := NewADPCMCodec(variant)
+		require.NoError(t, err)
+		split, err := NewADPCMCodec(variant)
+		require.NoError(t, err)
+
+		// Odd-sized chunks split codes across bytes; the held-over bits
+		// must carry into the next call
+		encoded := whole.Encode(input)
+		var chunked []byte
+		for i := 0; i < len(input); i += 37 {
+			chunked = append(chunked, split.Encode(input[i:min(i+37, len(input))])...)
+		}
+		assert.Equal(t, encoded, chunked, "variant %d", variant)
+
+		decoded := whole.Decode(encoded)
+		assert.Len(t, decoded, len(input))
+		var rejoined []int16
+		for i := 0; i < len(encoded); i += 7 {
+			rejoined = append(rejoined, split.Decode(encoded[i:min(i+7, len(encoded))])...)
+		}
+		assert.Equal(t, decoded, rejoined, "variant %d", variant)
+
+		whole.Close()
+		split.Close()
+	}
+}
+
+func TestG711Stream(t *testing.T) {
+	codec, err := NewG711Codec(false)
+	require.NoError(t, err)
+	defer codec.Close()
+
+	input := make([]int16, 320)
+	for i := range input {
+		input[i] = int16(8000 * math.Sin(2*math.Pi*float64(i)/40))
+	}
+	pcm := Int16ToBytes(input, false)
+
+	// Byte-at-a-time writes must produce the same stream as a bulk Encode
+	var encoded bytes.Buffer
+	w := codec.NewEncodeWriter(&encoded)
+	for i := range pcm {
+		n, err := w.Write(pcm[i : i+1])
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+	}
+	assert.Equal(t, codec.Encode(input), encoded.Bytes())
+
+	// Odd-sized reads through the decoder match a bulk Decode
+	r := codec.NewDecodeReader(bytes.NewReader(encoded.Bytes()))
+	var decoded []byte
+	buf := make([]byte, 7)
+	for {
+		n, err := r.Read(buf)
+		decoded = append(decoded, buf[:n]...)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+	}
+	assert.Equal(t, Int16ToBytes(codec.Decode(encoded.Bytes()), false), decoded)
+}
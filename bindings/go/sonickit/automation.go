@@ -0,0 +1,74 @@
+package sonickit
+
+import "sort"
+
+const (
+	// automationRampSamples is the length over which a scheduled parameter
+	// change is interpolated to avoid zipper noise.
+	automationRampSamples = 64
+	// automationRampStep is the sub-block size used while ramping.
+	automationRampStep = 8
+)
+
+// paramEvent is a parameter change scheduled at a sample offset within the
+// next processed block.
+type paramEvent struct {
+	offset int
+	value  float32
+}
+
+// scheduleEvent adds an event to a schedule.
+func scheduleEvent(events []paramEvent, sampleOffset int, value float32) []paramEvent {
+	if sampleOffset < 0 {
+		sampleOffset = 0
+	}
+	return append(events, paramEvent{offset: sampleOffset, value: value})
+}
+
+// processAutomated runs process over input in segments, applying each event
+// at its offset with a short linear ramp from the previous value. set pushes
+// a parameter value to the processor. Returns the final parameter value.
+func processAutomated(input, output []int16, events []paramEvent, current float32,
+	set func(float32), process func(in, out []int16)) float32 {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].offset < events[j].offset
+	})
+
+	pos := 0
+	run := func(end int) {
+		if end > pos {
+			process(input[pos:end], output[pos:end])
+			pos = end
+		}
+	}
+	for i, ev := range events {
+		start := ev.offset
+		if start > len(input) {
+			start = len(input)
+		}
+		run(start)
+
+		// Ramp until the ramp length elapses or the next event begins
+		end := start + automationRampSamples
+		if i+1 < len(events) && events[i+1].offset < end {
+			end = events[i+1].offset
+		}
+		if end > len(input) {
+			end = len(input)
+		}
+		from := current
+		for pos < end {
+			next := pos + automationRampStep
+			if next > end {
+				next = end
+			}
+			t := float32(next-start) / float32(end-start)
+			set(from + (ev.value-from)*t)
+			run(next)
+		}
+		set(ev.value)
+		current = ev.value
+	}
+	run(len(input))
+	return current
+}
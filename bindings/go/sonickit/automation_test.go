@@ -0,0 +1,31 @@
+package sonickit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessAutomated(t *testing.T) {
+	input := make([]int16, 256)
+	output := make([]int16, 256)
+	var gain float32 = 1
+	process := func(in, out []int16) {
+		for i := range in {
+			out[i] = int16(gain * 1000)
+		}
+	}
+	events := []paramEvent{{offset: 128, value: 0}}
+	final := processAutomated(input, output, events, gain,
+		func(v float32) { gain = v }, process)
+
+	assert.Equal(t, float32(0), final)
+	assert.Equal(t, int16(1000), output[127])
+	// Ramp down over 64 samples, then hold
+	assert.Less(t, output[128], int16(1000))
+	assert.Greater(t, output[160], int16(0))
+	assert.Equal(t, int16(0), output[192])
+	for i := 129; i < 256; i++ {
+		assert.LessOrEqual(t, output[i], output[i-1])
+	}
+}
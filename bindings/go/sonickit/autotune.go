@@ -0,0 +1,142 @@
+package sonickit
+
+import (
+	"errors"
+	"math"
+)
+
+// Pitch range AutoTune searches for the sung note.
+const (
+	autoTuneMinHz = 70
+	autoTuneMaxHz = 1000
+)
+
+// autoTuneVoiced is the detector confidence below which a block is treated
+// as unvoiced and left uncorrected.
+const autoTuneVoiced = 0.5
+
+// AutoTune snaps the pitch of monophonic audio toward the nearest note of a
+// scale. It combines a PitchDetector, which tracks the sung pitch, with a
+// PitchShifter that applies the correction.
+type AutoTune struct {
+	sampleRate int
+	detector   *PitchDetector
+	shifter    *PitchShifter
+	scale      []int
+	strength   float32
+	window     []int16 // most recent input, for pitch analysis
+	windowSize int
+	lastHz     float32
+}
+
+// NewAutoTune creates a new pitch corrector with full correction strength.
+//
+// Parameters:
+//   - sampleRate: Audio sample rate in Hz
+//   - scale: Notes to snap to, as semitone offsets (0-11) above C; for
+//     example {0, 2, 4, 5, 7, 9, 11} for C major
+func NewAutoTune(sampleRate int, scale []int) (*AutoTune, error) {
+	if len(scale) == 0 {
+		return nil, errors.New("failed to create auto-tune: scale is empty")
+	}
+	for _, degree := range scale {
+		if err := validate("auto-tune", checkRange("scale degree", float64(degree), 0, 11)); err != nil {
+			return nil, err
+		}
+	}
+	detector, err := NewPitchDetector(sampleRate, autoTuneMinHz, autoTuneMaxHz)
+	if err != nil {
+		return nil, err
+	}
+	shifter, err := NewPitchShifter(sampleRate, 0)
+	if err != nil {
+		detector.Close()
+		return nil, err
+	}
+	windowSize := 2 * sampleRate / autoTuneMinHz
+	return &AutoTune{
+		sampleRate: sampleRate,
+		detector:   detector,
+		shifter:    shifter,
+		scale:      append([]int(nil), scale...),
+		strength:   1,
+		window:     make([]int16, 0, windowSize),
+		windowSize: windowSize,
+	}, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (a *AutoTune) SampleRate() int {
+	return a.sampleRate
+}
+
+// SetStrength sets how far the pitch is pulled toward the target note, from
+// 0.0 (no correction) to 1.0 (snap fully onto the note).
+func (a *AutoTune) SetStrength(strength float32) {
+	a.strength = strength
+}
+
+// LastDetectedHz returns the pitch detected in the most recent Process
+// call, or 0 if that audio was unvoiced.
+func (a *AutoTune) LastDetectedHz() float32 {
+	return a.lastHz
+}
+
+// Process corrects the pitch of the audio. The correction is re-evaluated
+// every call from the most recent input, so blocks of 10-20ms track a
+// moving voice well.
+//
+// Like PitchShifter.Process, the output may be shorter or longer than the
+// input; call Flush at end of stream to drain it.
+func (a *AutoTune) Process(input []int16) []int16 {
+	if a.shifter.handle == nil || len(input) == 0 {
+		return nil
+	}
+	a.window = append(a.window, input...)
+	if over := len(a.window) - a.windowSize; over > 0 {
+		copy(a.window, a.window[over:])
+		a.window = a.window[:a.windowSize]
+	}
+
+	var shift float32
+	a.lastHz = 0
+	if len(a.window) == a.windowSize {
+		hz, confidence := a.detector.Process(a.window)
+		if hz > 0 && confidence >= autoTuneVoiced {
+			a.lastHz = hz
+			shift = a.correction(hz)
+		}
+	}
+	a.shifter.SetPitch(shift)
+	return a.shifter.Process(input)
+}
+
+// correction returns the shift in semitones from hz toward the nearest
+// scale note, scaled by the strength.
+func (a *AutoTune) correction(hz float32) float32 {
+	note := 69 + 12*math.Log2(float64(hz)/440)
+	best := math.Inf(1)
+	for _, degree := range a.scale {
+		target := float64(degree) + 12*math.Round((note-float64(degree))/12)
+		if d := target - note; math.Abs(d) < math.Abs(best) {
+			best = d
+		}
+	}
+	return float32(best) * a.strength
+}
+
+// Flush drains the pitch shifter at end of stream. See PitchShifter.Flush.
+func (a *AutoTune) Flush() []int16 {
+	return a.shifter.Flush()
+}
+
+// Latency returns the processing delay in samples.
+func (a *AutoTune) Latency() int {
+	return a.shifter.Latency()
+}
+
+// Close releases the detector and shifter.
+func (a *AutoTune) Close() error {
+	a.detector.Close()
+	return a.shifter.Close()
+}
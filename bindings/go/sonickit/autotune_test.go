@@ -0,0 +1,65 @@
+package sonickit
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoTune(t *testing.T) {
+	const sampleRate = 48000
+	cMajor := []int{0, 2, 4, 5, 7, 9, 11}
+	tuner, err := NewAutoTune(sampleRate, cMajor)
+	require.NoError(t, err)
+	defer tuner.Close()
+
+	// A3 sung 40 cents flat
+	flatHz := 220 * math.Pow(2, -40.0/1200)
+	input := make([]int16, sampleRate)
+	for i := range input {
+		input[i] = int16(10000 * math.Sin(2*math.Pi*flatHz*float64(i)/sampleRate))
+	}
+
+	var output []int16
+	for start := 0; start < len(input); start += 512 {
+		end := start + 512
+		if end > len(input) {
+			end = len(input)
+		}
+		output = append(output, tuner.Process(input[start:end])...)
+	}
+	assert.InDelta(t, flatHz, tuner.LastDetectedHz(), 1)
+
+	detector, err := NewPitchDetector(sampleRate, 70, 1000)
+	require.NoError(t, err)
+	defer detector.Close()
+	require.Greater(t, len(output), 4800)
+	hz, _ := detector.Process(output[len(output)-4800:])
+	require.Greater(t, hz, float32(0))
+
+	before := math.Abs(1200 * math.Log2(flatHz/220))
+	after := math.Abs(1200 * math.Log2(float64(hz)/220))
+	assert.Less(t, after, before, "corrected to %.2f Hz", hz)
+}
+
+func TestAutoTuneCorrection(t *testing.T) {
+	tuner, err := NewAutoTune(48000, []int{0, 4, 7})
+	require.NoError(t, err)
+	defer tuner.Close()
+
+	// E4 (329.63Hz) is in the scale; F4 (349.23Hz) snaps down to E4 and
+	// B3 (246.94Hz) up to C4
+	assert.InDelta(t, 0, tuner.correction(329.63), 0.01)
+	assert.InDelta(t, -1, tuner.correction(349.23), 0.01)
+	assert.InDelta(t, 1, tuner.correction(246.94), 0.01)
+
+	tuner.SetStrength(0.5)
+	assert.InDelta(t, -0.5, tuner.correction(349.23), 0.01)
+
+	_, err = NewAutoTune(48000, []int{0, 12})
+	assert.ErrorIs(t, err, ErrInvalidRange)
+	_, err = NewAutoTune(48000, nil)
+	assert.Error(t, err)
+}
@@ -3,10 +3,12 @@ package sonickit
 /*
 #include <stdlib.h>
 #include "codec/voice_g711.h"
+#include "codec/voice_g722.h"
+#include "codec/voice_adpcm.h"
 */
 import "C"
 import (
-	"errors"
+	"fmt"
 	"runtime"
 	"unsafe"
 )
@@ -26,9 +28,10 @@ func NewG711Codec(useAlaw bool) (*G711Codec, error) {
 	if useAlaw {
 		alawInt = 1
 	}
+	clearError()
 	handle := C.voice_g711_create(alawInt)
 	if handle == nil {
-		return nil, errors.New("failed to create G.711 codec")
+		return nil, createError("G.711 codec")
 	}
 	c := &G711Codec{handle: handle, isAlaw: useAlaw}
 	runtime.SetFinalizer(c, (*G711Codec).Close)
@@ -75,3 +78,171 @@ func (c *G711Codec) Close() error {
 	}
 	return nil
 }
+
+// G722Codec provides G.722 wideband encoding and decoding. It takes 16kHz
+// PCM and produces a 64kbit/s stream of one byte per two samples.
+type G722Codec struct {
+	handle unsafe.Pointer
+}
+
+// NewG722Codec creates a new G.722 codec.
+func NewG722Codec() (*G722Codec, error) {
+	clearError()
+	handle := C.voice_g722_create()
+	if handle == nil {
+		return nil, createError("G.722 codec")
+	}
+	c := &G722Codec{handle: handle}
+	runtime.SetFinalizer(c, (*G722Codec).Close)
+	return c, nil
+}
+
+// Encode encodes 16kHz 16-bit PCM samples to G.722. Samples are coded in
+// pairs; a trailing odd sample is ignored.
+func (c *G722Codec) Encode(input []int16) []byte {
+	n := len(input) &^ 1
+	if c.handle == nil || n == 0 {
+		return nil
+	}
+	output := make([]byte, n/2)
+	C.voice_g722_encode(c.handle,
+		(*C.short)(unsafe.Pointer(&input[0])),
+		(*C.uchar)(unsafe.Pointer(&output[0])),
+		C.int(n))
+	return output
+}
+
+// Decode decodes G.722 data to 16kHz 16-bit PCM samples.
+func (c *G722Codec) Decode(input []byte) []int16 {
+	if c.handle == nil || len(input) == 0 {
+		return nil
+	}
+	output := make([]int16, len(input)*2)
+	C.voice_g722_decode(c.handle,
+		(*C.uchar)(unsafe.Pointer(&input[0])),
+		(*C.short)(unsafe.Pointer(&output[0])),
+		C.int(len(input)))
+	return output
+}
+
+// Close releases the codec resources.
+func (c *G722Codec) Close() error {
+	if c.handle != nil {
+		C.voice_g722_destroy(c.handle)
+		c.handle = nil
+		runtime.SetFinalizer(c, nil)
+	}
+	return nil
+}
+
+// ADPCMVariant selects the ADPCM coding scheme.
+type ADPCMVariant int
+
+const (
+	// ADPCMIMA is IMA/DVI ADPCM at 4 bits per sample.
+	ADPCMIMA ADPCMVariant = 0
+	// ADPCMG726Kbps16 is G.726 at 16kbit/s (2 bits per sample).
+	ADPCMG726Kbps16 ADPCMVariant = 1
+	// ADPCMG726Kbps24 is G.726 at 24kbit/s (3 bits per sample).
+	ADPCMG726Kbps24 ADPCMVariant = 2
+	// ADPCMG726Kbps32 is G.726 at 32kbit/s (4 bits per sample).
+	ADPCMG726Kbps32 ADPCMVariant = 3
+	// ADPCMG726Kbps40 is G.726 at 40kbit/s (5 bits per sample).
+	ADPCMG726Kbps40 ADPCMVariant = 4
+)
+
+// BitsPerSample returns the number of code bits per sample for the variant,
+// or 0 for an unknown variant.
+func (v ADPCMVariant) BitsPerSample() int {
+	switch v {
+	case ADPCMIMA, ADPCMG726Kbps32:
+		return 4
+	case ADPCMG726Kbps16:
+		return 2
+	case ADPCMG726Kbps24:
+		return 3
+	case ADPCMG726Kbps40:
+		return 5
+	}
+	return 0
+}
+
+// ADPCMCodec provides IMA and G.726 ADPCM encoding and decoding of 8kHz
+// PCM. Predictor state and any partially filled code byte carry over
+// between calls, so a stream may be coded in arbitrary chunks.
+type ADPCMCodec struct {
+	handle  unsafe.Pointer
+	variant ADPCMVariant
+	bits    int
+}
+
+// NewADPCMCodec creates a new ADPCM codec.
+//
+// Parameters:
+//   - variant: Coding scheme and bitrate
+func NewADPCMCodec(variant ADPCMVariant) (*ADPCMCodec, error) {
+	bits := variant.BitsPerSample()
+	if bits == 0 {
+		return nil, fmt.Errorf("unknown ADPCM variant %d", variant)
+	}
+	clearError()
+	handle := C.voice_adpcm_create(C.int(variant))
+	if handle == nil {
+		return nil, createError("ADPCM codec")
+	}
+	c := &ADPCMCodec{handle: handle, variant: variant, bits: bits}
+	runtime.SetFinalizer(c, (*ADPCMCodec).Close)
+	return c, nil
+}
+
+// Variant returns the coding scheme the codec was created with.
+func (c *ADPCMCodec) Variant() ADPCMVariant {
+	return c.variant
+}
+
+// Encode encodes 16-bit PCM samples to ADPCM. Code bits that do not fill
+// a whole byte are held until the next call.
+func (c *ADPCMCodec) Encode(input []int16) []byte {
+	if c.handle == nil || len(input) == 0 {
+		return nil
+	}
+	// Room for the held-over bits of the previous call as well
+	output := make([]byte, (len(input)*c.bits+7)/8+1)
+	n := C.voice_adpcm_encode(c.handle,
+		(*C.short)(unsafe.Pointer(&input[0])),
+		C.int(len(input)),
+		(*C.uchar)(unsafe.Pointer(&output[0])),
+		C.int(len(output)))
+	if n <= 0 {
+		return nil
+	}
+	return output[:n]
+}
+
+// Decode decodes ADPCM data to 16-bit PCM samples. Code bits that do not
+// complete a sample are held until the next call.
+func (c *ADPCMCodec) Decode(input []byte) []int16 {
+	if c.handle == nil || len(input) == 0 {
+		return nil
+	}
+	output := make([]int16, len(input)*8/c.bits+1)
+	n := C.voice_adpcm_decode(c.handle,
+		(*C.uchar)(unsafe.Pointer(&input[0])),
+		C.int(len(input)),
+		(*C.short)(unsafe.Pointer(&output[0])),
+		C.int(len(output)))
+	if n <= 0 {
+		return nil
+	}
+	return output[:n]
+}
+
+// Close releases the codec resources.
+func (c *ADPCMCodec) Close() error {
+	if c.handle != nil {
+		C.voice_adpcm_destroy(c.handle)
+		c.handle = nil
+		runtime.SetFinalizer(c, nil)
+	}
+	return nil
+}
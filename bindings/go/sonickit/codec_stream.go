@@ -0,0 +1,96 @@
+package sonickit
+
+import (
+	"errors"
+	"io"
+)
+
+// g711EncodeWriter encodes little-endian PCM16 bytes written to it and
+// forwards the G.711 bytes to the underlying writer.
+type g711EncodeWriter struct {
+	codec   *G711Codec
+	w       io.Writer
+	pending []byte // odd trailing byte carried over to the next Write
+}
+
+// NewEncodeWriter returns a writer that accepts little-endian 16-bit PCM
+// bytes and writes the encoded G.711 stream to w.
+//
+// Writes need not be sample aligned: a trailing odd byte is held until the
+// next Write completes the sample.
+func (c *G711Codec) NewEncodeWriter(w io.Writer) io.Writer {
+	return &g711EncodeWriter{codec: c, w: w}
+}
+
+func (e *g711EncodeWriter) Write(p []byte) (int, error) {
+	if e.codec.handle == nil {
+		return 0, errors.New("G.711 codec is closed")
+	}
+	data := p
+	if len(e.pending) > 0 {
+		data = append([]byte{e.pending[0]}, p...)
+		e.pending = nil
+	}
+	whole := len(data) &^ 1
+	if whole < len(data) {
+		e.pending = []byte{data[whole]}
+	}
+	if whole == 0 {
+		return len(p), nil
+	}
+
+	samples, err := BytesToInt16(data[:whole], false)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := e.w.Write(e.codec.Encode(samples)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// g711DecodeReader decodes G.711 bytes from the underlying reader and serves
+// them as little-endian PCM16 bytes.
+type g711DecodeReader struct {
+	codec *G711Codec
+	r     io.Reader
+	in    []byte
+	out   []byte // decoded bytes not yet returned to the caller
+	err   error  // sticky error from the underlying reader
+}
+
+// NewDecodeReader returns a reader that decodes the G.711 stream read from r
+// into little-endian 16-bit PCM bytes.
+//
+// Reads need not be sample aligned: decoded bytes that do not fit in the
+// caller's buffer are returned by the next Read.
+func (c *G711Codec) NewDecodeReader(r io.Reader) io.Reader {
+	return &g711DecodeReader{codec: c, r: r}
+}
+
+func (d *g711DecodeReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for len(d.out) == 0 {
+		if d.err != nil {
+			return 0, d.err
+		}
+		if d.codec.handle == nil {
+			return 0, errors.New("G.711 codec is closed")
+		}
+		// One encoded byte expands to two PCM bytes
+		want := (len(p) + 1) / 2
+		if cap(d.in) < want {
+			d.in = make([]byte, want)
+		}
+		n, err := d.r.Read(d.in[:want])
+		d.err = err
+		if n > 0 {
+			d.out = Int16ToBytes(d.codec.Decode(d.in[:n]), false)
+		}
+	}
+	n := copy(p, d.out)
+	d.out = d.out[n:]
+	return n, nil
+}
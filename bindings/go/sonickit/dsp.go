@@ -11,10 +11,23 @@ package sonickit
 #include "dsp/voice_equalizer.h"
 #include "dsp/voice_compressor.h"
 #include "dsp/voice_comfort_noise.h"
+#include "dsp/voice_feedback.h"
+#include "dsp/voice_tempo.h"
+#include "dsp/voice_onset.h"
+#include "dsp/voice_pitch_detect.h"
+#include "dsp/voice_fingerprint.h"
+#include "dsp/voice_declick.h"
+#include "dsp/voice_specgate.h"
+#include "dsp/voice_fft.h"
+#include "dsp/voice_mbcomp.h"
+#include "dsp/voice_crossover.h"
 */
 import "C"
 import (
 	"errors"
+	"fmt"
+	"math"
+	"math/bits"
 	"runtime"
 	"unsafe"
 )
@@ -27,12 +40,20 @@ const (
 	DenoiserSpeexDSP DenoiserEngine = 0
 	// DenoiserRNNoise uses the RNNoise neural network-based algorithm.
 	DenoiserRNNoise DenoiserEngine = 1
+	// DenoiserSpectralSub uses classic spectral subtraction, which is
+	// lighter than RNNoise and suited to low-CPU embedded targets.
+	DenoiserSpectralSub DenoiserEngine = 2
 )
 
 // Denoiser performs noise reduction on audio samples.
 type Denoiser struct {
-	handle    unsafe.Pointer
-	frameSize int
+	handle     unsafe.Pointer
+	sampleRate int
+	frameSize  int
+	engine     DenoiserEngine
+	chunks     frameChunker
+	wet        float64 // processed share of the output, see SetMix
+	dry        []int16 // copy of the input kept for SetMix when dst aliases src
 }
 
 // NewDenoiser creates a new noise reduction processor.
@@ -42,33 +63,209 @@ type Denoiser struct {
 //   - frameSize: Number of samples per frame
 //   - engine: Noise reduction algorithm to use
 func NewDenoiser(sampleRate, frameSize int, engine DenoiserEngine) (*Denoiser, error) {
+	if err := validate("denoiser", checkSampleRate(sampleRate), checkFrameSize(frameSize)); err != nil {
+		return nil, err
+	}
+	clearError()
 	handle := C.voice_denoise_create(C.int(sampleRate), C.int(frameSize), C.int(engine))
 	if handle == nil {
-		return nil, errors.New("failed to create denoiser")
+		if engine == DenoiserRNNoise && !HasFeature("rnnoise") {
+			return nil, errors.New("failed to create denoiser: RNNoise support not compiled in")
+		}
+		return nil, createError("denoiser")
+	}
+	d := &Denoiser{
+		handle:     handle,
+		sampleRate: sampleRate,
+		frameSize:  frameSize,
+		engine:     engine,
+		chunks:     frameChunker{frameSize: frameSize},
+		wet:        1,
 	}
-	d := &Denoiser{handle: handle, frameSize: frameSize}
 	runtime.SetFinalizer(d, (*Denoiser).Close)
 	return d, nil
 }
 
+// SampleRate returns the sample rate in Hz.
+func (d *Denoiser) SampleRate() int {
+	return d.sampleRate
+}
+
+// FrameSize returns the number of samples per frame.
+func (d *Denoiser) FrameSize() int {
+	return d.frameSize
+}
+
+// Latency returns the most samples Process can hold back while it waits for
+// a whole frame, FrameSize-1.
+func (d *Denoiser) Latency() int {
+	return d.frameSize - 1
+}
+
 // Process applies noise reduction to the input samples.
+//
+// The denoiser works on whole frames of FrameSize samples. Input of any
+// length is accepted: a trailing partial frame is held back until the next
+// call completes it, so the output can be shorter or longer than the input.
+// Call Flush at end of stream to drain it. When every call passes a
+// multiple of FrameSize samples nothing is held back and the output has the
+// same length as the input.
 func (d *Denoiser) Process(input []int16) []int16 {
 	if d.handle == nil || len(input) == 0 {
 		return nil
 	}
-	output := make([]int16, len(input))
+	frames := d.chunks.split(input)
+	if len(frames) == 0 {
+		return nil
+	}
+	output := make([]int16, len(frames))
+	d.process(output, frames)
+	return output
+}
+
+// Flush processes the partial frame held back by Process, zero-padded to a
+// whole frame, and returns its real samples. It returns nil if nothing is
+// held back.
+func (d *Denoiser) Flush() []int16 {
+	if d.handle == nil {
+		return nil
+	}
+	frame, n := d.chunks.flush()
+	if n == 0 {
+		return nil
+	}
+	output := make([]int16, d.frameSize)
+	d.process(output, frame)
+	return output[:n]
+}
+
+// ProcessInto applies noise reduction to src, writing the result to dst,
+// which may be src itself, and returns the number of samples written.
+// Unlike Process it does not allocate, so dst can come from a FramePool,
+// and it does no buffering: src must be a whole number of frames, and
+// Process must not be holding back a partial frame (call Flush first). If
+// these do not hold, dst is shorter than src or the denoiser is closed,
+// nothing is written and 0 is returned.
+func (d *Denoiser) ProcessInto(dst, src []int16) int {
+	if d.handle == nil || len(src) == 0 || len(dst) < len(src) || !d.chunks.direct(len(src)) {
+		return 0
+	}
+	d.process(dst, src)
+	return len(src)
+}
+
+// process denoises whole frames from src into dst, which may be the same
+// buffer.
+func (d *Denoiser) process(dst, src []int16) {
+	dry := src
+	if d.wet < 1 {
+		d.dry = append(d.dry[:0], src...)
+		dry = d.dry
+	}
 	C.voice_denoise_process(d.handle,
-		(*C.short)(unsafe.Pointer(&input[0])),
-		(*C.short)(unsafe.Pointer(&output[0])),
+		(*C.short)(unsafe.Pointer(&src[0])),
+		(*C.short)(unsafe.Pointer(&dst[0])),
+		C.int(len(src)))
+	d.blend(dst[:len(src)], dry)
+}
+
+// Process32 applies noise reduction to 24-bit samples (see Int24Min). Like
+// ProcessInto it does no buffering, and returns nil unless input is a
+// whole number of frames and Process holds nothing back.
+func (d *Denoiser) Process32(input []int32) []int32 {
+	if d.handle == nil || len(input) == 0 || !d.chunks.direct(len(input)) {
+		return nil
+	}
+	output := make([]int32, len(input))
+	C.voice_denoise_process_s32(d.handle,
+		(*C.int)(unsafe.Pointer(&input[0])),
+		(*C.int)(unsafe.Pointer(&output[0])),
 		C.int(len(input)))
+	if d.wet < 1 {
+		for i, s := range input {
+			output[i] = int32(math.Round(float64(s) + d.wet*float64(output[i]-s)))
+		}
+	}
 	return output
 }
 
-// SetLevel sets the noise reduction level (0-100).
+// ProcessWithNoise applies noise reduction like Process, buffering partial
+// frames the same way, and also returns the denoiser's estimate of the
+// removed noise level for the last frame, in dBFS. The estimate can drive a
+// noise floor display or automatic SetLevel adjustment. If no whole frame
+// is ready it returns nil and -100.
+func (d *Denoiser) ProcessWithNoise(input []int16) (clean []int16, noiseDb float32) {
+	if d.handle == nil || len(input) == 0 {
+		return nil, -100
+	}
+	frames := d.chunks.split(input)
+	if len(frames) == 0 {
+		return nil, -100
+	}
+	clean = make([]int16, len(frames))
+	var noise C.float
+	for i := 0; i < len(frames); i += d.frameSize {
+		C.voice_denoise_process_ex(d.handle,
+			(*C.short)(unsafe.Pointer(&frames[i])),
+			(*C.short)(unsafe.Pointer(&clean[i])),
+			C.int(d.frameSize),
+			&noise)
+	}
+	d.blend(clean, frames)
+	return clean, float32(noise)
+}
+
+// SetMix sets the dry/wet balance, where 0 passes the input through
+// unchanged and 1, the default, is full noise reduction. Values outside
+// that range are clamped. The denoiser keeps running at every setting, so
+// its noise estimate stays current and the mix can be raised gradually to
+// fade denoising in, or switched between 0 and 1 for A/B comparison.
+func (d *Denoiser) SetMix(wet float32) {
+	d.wet = math.Max(0, math.Min(1, float64(wet)))
+}
+
+// blend mixes the dry input back into the denoised output in place,
+// according to SetMix.
+func (d *Denoiser) blend(clean, input []int16) {
+	if d.wet >= 1 {
+		return
+	}
+	for i, s := range input {
+		clean[i] = clampInt16(float64(s) + d.wet*(float64(clean[i])-float64(s)))
+	}
+}
+
+// SetLevel sets the noise reduction level (0-100) immediately.
+// It is equivalent to SetLevelRamp(level, 0).
 func (d *Denoiser) SetLevel(level int) {
+	d.SetLevelRamp(level, 0)
+}
+
+// SetLevelRamp moves the noise reduction level (0-100) to level, interpolating
+// over rampMs milliseconds of processed audio to avoid an audible step.
+func (d *Denoiser) SetLevelRamp(level, rampMs int) {
 	if d.handle != nil {
-		C.voice_denoise_set_level(d.handle, C.int(level))
+		C.voice_denoise_set_level_ramp(d.handle, C.int(level), C.int(rampMs))
+	}
+}
+
+// SetOverSubtraction sets the over-subtraction factor of the
+// DenoiserSpectralSub engine (typically 1.0-4.0). Larger factors remove
+// more noise at the cost of musical-noise artifacts. It is a no-op for the
+// other engines.
+func (d *Denoiser) SetOverSubtraction(factor float32) {
+	if d.handle != nil && d.engine == DenoiserSpectralSub {
+		C.voice_denoise_set_over_subtraction(d.handle, C.float(factor))
+	}
+}
+
+// Level returns the effective noise reduction level, which trails the target
+// while a ramp is in progress.
+func (d *Denoiser) Level() int {
+	if d.handle == nil {
+		return 0
 	}
+	return int(C.voice_denoise_get_level(d.handle))
 }
 
 // Close releases the denoiser resources.
@@ -83,8 +280,12 @@ func (d *Denoiser) Close() error {
 
 // EchoCanceller performs acoustic echo cancellation.
 type EchoCanceller struct {
-	handle    unsafe.Pointer
-	frameSize int
+	handle       unsafe.Pointer
+	sampleRate   int
+	frameSize    int
+	filterLength int
+	captured     frameChunker
+	playback     frameChunker
 }
 
 // NewEchoCanceller creates a new echo cancellation processor.
@@ -94,15 +295,42 @@ type EchoCanceller struct {
 //   - frameSize: Number of samples per frame
 //   - filterLength: Echo tail length in samples
 func NewEchoCanceller(sampleRate, frameSize, filterLength int) (*EchoCanceller, error) {
+	if err := validate("echo canceller", checkSampleRate(sampleRate), checkFrameSize(frameSize)); err != nil {
+		return nil, err
+	}
+	clearError()
 	handle := C.voice_aec_create(C.int(sampleRate), C.int(frameSize), C.int(filterLength))
 	if handle == nil {
-		return nil, errors.New("failed to create echo canceller")
+		return nil, createError("echo canceller")
+	}
+	e := &EchoCanceller{
+		handle:       handle,
+		sampleRate:   sampleRate,
+		frameSize:    frameSize,
+		filterLength: filterLength,
+		captured:     frameChunker{frameSize: frameSize},
+		playback:     frameChunker{frameSize: frameSize},
 	}
-	e := &EchoCanceller{handle: handle, frameSize: frameSize}
 	runtime.SetFinalizer(e, (*EchoCanceller).Close)
 	return e, nil
 }
 
+// SampleRate returns the sample rate in Hz.
+func (e *EchoCanceller) SampleRate() int {
+	return e.sampleRate
+}
+
+// FrameSize returns the number of samples per frame.
+func (e *EchoCanceller) FrameSize() int {
+	return e.frameSize
+}
+
+// Latency returns the most samples Process can hold back while it waits for
+// a whole frame, FrameSize-1.
+func (e *EchoCanceller) Latency() int {
+	return e.frameSize - 1
+}
+
 // Process applies echo cancellation.
 //
 // Parameters:
@@ -110,17 +338,69 @@ func NewEchoCanceller(sampleRate, frameSize, filterLength int) (*EchoCanceller,
 //   - playback: Reference signal being played to speaker
 //
 // Returns the echo-cancelled audio.
+//
+// Like Denoiser.Process, input of any length is accepted: a trailing
+// partial frame of both signals is held back until the next call, and
+// Flush drains it at end of stream. playback is matched to the length of
+// captured, padded with silence or truncated.
 func (e *EchoCanceller) Process(captured, playback []int16) []int16 {
 	if e.handle == nil || len(captured) == 0 {
 		return nil
 	}
-	output := make([]int16, len(captured))
+	if len(playback) != len(captured) {
+		aligned := make([]int16, len(captured))
+		copy(aligned, playback)
+		playback = aligned
+	}
+	capFrames := e.captured.split(captured)
+	playFrames := e.playback.split(playback)
+	if len(capFrames) == 0 {
+		return nil
+	}
+	output := make([]int16, len(capFrames))
+	for i := 0; i < len(capFrames); i += e.frameSize {
+		e.process(capFrames[i:i+e.frameSize], playFrames[i:i+e.frameSize], output[i:i+e.frameSize])
+	}
+	return output
+}
+
+// Flush cancels echo in the partial frame held back by Process, zero-padded
+// to a whole frame, and returns its real samples. It returns nil if nothing
+// is held back.
+func (e *EchoCanceller) Flush() []int16 {
+	if e.handle == nil {
+		return nil
+	}
+	capFrame, n := e.captured.flush()
+	playFrame, _ := e.playback.flush()
+	if n == 0 {
+		return nil
+	}
+	output := make([]int16, e.frameSize)
+	e.process(capFrame, playFrame, output)
+	return output[:n]
+}
+
+func (e *EchoCanceller) process(captured, playback, output []int16) {
 	C.voice_aec_process(e.handle,
 		(*C.short)(unsafe.Pointer(&captured[0])),
 		(*C.short)(unsafe.Pointer(&playback[0])),
 		(*C.short)(unsafe.Pointer(&output[0])),
 		C.int(len(captured)))
-	return output
+}
+
+// FilterTaps returns a copy of the adaptive filter's current coefficients,
+// one per sample of echo tail (filterLength in total). The copy is a
+// snapshot for inspection; modifying it does not affect the canceller.
+func (e *EchoCanceller) FilterTaps() []float32 {
+	if e.handle == nil || e.filterLength <= 0 {
+		return nil
+	}
+	taps := make([]float32, e.filterLength)
+	n := C.voice_aec_get_taps(e.handle,
+		(*C.float)(unsafe.Pointer(&taps[0])),
+		C.int(len(taps)))
+	return taps[:n]
 }
 
 // Close releases the echo canceller resources.
@@ -145,30 +425,114 @@ const (
 
 // Agc performs automatic gain control.
 type Agc struct {
-	handle    unsafe.Pointer
-	frameSize int
+	handle     unsafe.Pointer
+	sampleRate int
+	frameSize  int
+	chunks     frameChunker
 }
 
 // NewAgc creates a new automatic gain control processor.
 func NewAgc(sampleRate, frameSize int, mode AgcMode, targetLevel int) (*Agc, error) {
+	if err := validate("AGC", checkSampleRate(sampleRate), checkFrameSize(frameSize)); err != nil {
+		return nil, err
+	}
+	clearError()
 	handle := C.voice_agc_create(C.int(sampleRate), C.int(frameSize), C.int(mode), C.int(targetLevel))
 	if handle == nil {
-		return nil, errors.New("failed to create AGC")
+		return nil, createError("AGC")
+	}
+	a := &Agc{
+		handle:     handle,
+		sampleRate: sampleRate,
+		frameSize:  frameSize,
+		chunks:     frameChunker{frameSize: frameSize},
 	}
-	a := &Agc{handle: handle, frameSize: frameSize}
 	runtime.SetFinalizer(a, (*Agc).Close)
 	return a, nil
 }
 
+// SampleRate returns the sample rate in Hz.
+func (a *Agc) SampleRate() int {
+	return a.sampleRate
+}
+
+// FrameSize returns the number of samples per frame.
+func (a *Agc) FrameSize() int {
+	return a.frameSize
+}
+
+// Latency returns the most samples Process can hold back while it waits for
+// a whole frame, FrameSize-1.
+func (a *Agc) Latency() int {
+	return a.frameSize - 1
+}
+
 // Process applies automatic gain control.
+//
+// Like Denoiser.Process, input of any length is accepted: a trailing
+// partial frame is held back until the next call, and Flush drains it at
+// end of stream.
 func (a *Agc) Process(input []int16) []int16 {
 	if a.handle == nil || len(input) == 0 {
 		return nil
 	}
-	output := make([]int16, len(input))
+	frames := a.chunks.split(input)
+	if len(frames) == 0 {
+		return nil
+	}
+	output := make([]int16, len(frames))
+	a.process(output, frames)
+	return output
+}
+
+// Flush applies gain control to the partial frame held back by Process,
+// zero-padded to a whole frame, and returns its real samples. It returns
+// nil if nothing is held back.
+func (a *Agc) Flush() []int16 {
+	if a.handle == nil {
+		return nil
+	}
+	frame, n := a.chunks.flush()
+	if n == 0 {
+		return nil
+	}
+	output := make([]int16, a.frameSize)
+	a.process(output, frame)
+	return output[:n]
+}
+
+// ProcessInto applies automatic gain control to src, writing the result to
+// dst without allocating. It returns len(src), or 0 if dst is shorter than
+// src or the AGC is closed. Like Denoiser.ProcessInto it does no
+// buffering, so it also returns 0 unless src is a whole number of frames
+// and Process holds nothing back.
+func (a *Agc) ProcessInto(dst, src []int16) int {
+	if a.handle == nil || len(src) == 0 || len(dst) < len(src) || !a.chunks.direct(len(src)) {
+		return 0
+	}
+	a.process(dst, src)
+	return len(src)
+}
+
+// process applies gain control to whole frames from src into dst.
+func (a *Agc) process(dst, src []int16) {
 	C.voice_agc_process(a.handle,
-		(*C.short)(unsafe.Pointer(&input[0])),
-		(*C.short)(unsafe.Pointer(&output[0])),
+		(*C.short)(unsafe.Pointer(&src[0])),
+		(*C.short)(unsafe.Pointer(&dst[0])),
+		C.int(len(src)))
+}
+
+// Process32 applies automatic gain control to 24-bit samples (see Int24Min).
+// Like ProcessInto it does no buffering, and returns nil unless input is a
+// whole number of frames and Process holds nothing back.
+func (a *Agc) Process32(input []int32) []int32 {
+	if a.handle == nil || len(input) == 0 || !a.chunks.direct(len(input)) {
+		return nil
+	}
+	output := make([]int32, len(input))
+	C.voice_agc_process_s32(a.handle,
+		(*C.int)(unsafe.Pointer(&input[0])),
+		(*C.int)(unsafe.Pointer(&output[0])),
 		C.int(len(input)))
 	return output
 }
@@ -207,22 +571,33 @@ const (
 
 // Vad performs voice activity detection.
 type Vad struct {
-	handle    unsafe.Pointer
-	frameSize int
+	handle     unsafe.Pointer
+	sampleRate int
+	frameSize  int
 }
 
 // NewVad creates a new voice activity detector.
 func NewVad(sampleRate int, mode VadMode) (*Vad, error) {
+	if err := validate("VAD", checkSampleRate(sampleRate)); err != nil {
+		return nil, err
+	}
+	clearError()
 	handle := C.voice_vad_create(C.int(sampleRate), C.int(mode))
 	if handle == nil {
-		return nil, errors.New("failed to create VAD")
+		return nil, createError("VAD")
 	}
-	v := &Vad{handle: handle}
+	v := &Vad{handle: handle, sampleRate: sampleRate}
 	runtime.SetFinalizer(v, (*Vad).Close)
 	return v, nil
 }
 
-// IsSpeech detects if the audio frame contains speech.
+// SampleRate returns the sample rate in Hz.
+func (v *Vad) SampleRate() int {
+	return v.sampleRate
+}
+
+// IsSpeech detects if the audio frame contains speech. The frame must be
+// 10, 20 or 30ms long; see RecommendedFrameSize.
 func (v *Vad) IsSpeech(input []int16) bool {
 	if v.handle == nil || len(input) == 0 {
 		return false
@@ -233,6 +608,41 @@ func (v *Vad) IsSpeech(input []int16) bool {
 	return result != 0
 }
 
+// AnalyzeBlock splits input into consecutive sub-frames of subFrameSamples
+// (e.g. 160 for 10ms at 16kHz) and returns one speech decision per
+// sub-frame, in order. len(input) must be a multiple of subFrameSamples;
+// otherwise nil is returned.
+func (v *Vad) AnalyzeBlock(input []int16, subFrameSamples int) []bool {
+	if v.handle == nil || !validSubFrames(input, subFrameSamples) {
+		return nil
+	}
+	decisions := make([]bool, len(input)/subFrameSamples)
+	for i := range decisions {
+		decisions[i] = v.IsSpeech(input[i*subFrameSamples : (i+1)*subFrameSamples])
+	}
+	return decisions
+}
+
+// EnergyEnvelope returns the RMS level in dBFS of each sub-frame of
+// subFrameSamples, floored at -100. len(input) must be a multiple of
+// subFrameSamples; otherwise nil is returned. It does not affect the
+// detector state.
+func (v *Vad) EnergyEnvelope(input []int16, subFrameSamples int) []float32 {
+	if !validSubFrames(input, subFrameSamples) {
+		return nil
+	}
+	envelope := make([]float32, len(input)/subFrameSamples)
+	for i := range envelope {
+		envelope[i] = rmsDbfs(input[i*subFrameSamples : (i+1)*subFrameSamples])
+	}
+	return envelope
+}
+
+// validSubFrames reports whether input divides evenly into sub-frames.
+func validSubFrames(input []int16, subFrameSamples int) bool {
+	return subFrameSamples > 0 && len(input) > 0 && len(input)%subFrameSamples == 0
+}
+
 // GetProbability returns the speech probability (0.0-1.0).
 func (v *Vad) GetProbability() float32 {
 	if v.handle == nil {
@@ -257,6 +667,10 @@ type Resampler struct {
 	channels int
 	inRate   int
 	outRate  int
+	driftPPM float32
+	pending  []int16 // resampled output not yet returned by ProcessFixed
+	inLen    C.uint  // in/out lengths kept here so passing their
+	outLen   C.uint  // addresses to C does not allocate per call
 }
 
 // NewResampler creates a new sample rate converter.
@@ -267,9 +681,18 @@ type Resampler struct {
 //   - outRate: Output sample rate in Hz
 //   - quality: Resampling quality (0-10, higher is better)
 func NewResampler(channels, inRate, outRate, quality int) (*Resampler, error) {
+	if err := validate("resampler",
+		checkChannels(channels),
+		checkPositiveRate(inRate),
+		checkPositiveRate(outRate),
+		checkRange("quality", float64(quality), 0, 10),
+	); err != nil {
+		return nil, err
+	}
+	clearError()
 	handle := C.voice_resampler_create(C.int(channels), C.int(inRate), C.int(outRate), C.int(quality))
 	if handle == nil {
-		return nil, errors.New("failed to create resampler")
+		return nil, createError("resampler")
 	}
 	r := &Resampler{
 		handle:   handle,
@@ -281,25 +704,114 @@ func NewResampler(channels, inRate, outRate, quality int) (*Resampler, error) {
 	return r, nil
 }
 
+// SampleRate returns the input sample rate in Hz; it is the same as InRate.
+func (r *Resampler) SampleRate() int {
+	return r.inRate
+}
+
+// InRate returns the input sample rate in Hz.
+func (r *Resampler) InRate() int {
+	return r.inRate
+}
+
+// OutRate returns the output sample rate in Hz.
+func (r *Resampler) OutRate() int {
+	return r.outRate
+}
+
 // Process resamples the input audio.
 func (r *Resampler) Process(input []int16) []int16 {
 	if r.handle == nil || len(input) == 0 {
 		return nil
 	}
-	// Calculate output size based on ratio
-	outLen := (len(input) * r.outRate) / r.inRate
+	output := make([]int16, r.maxOutput(len(input)))
+	return r.process(input, output)
+}
+
+// ProcessReuse resamples input into scratch, avoiding a per-call allocation
+// on real-time paths.
+//
+// scratch is only reallocated when its capacity is too small for the
+// resampled output, so after the first few calls at a steady block size no
+// allocation happens at all. The returned slice aliases scratch (or its
+// replacement): it is overwritten by the next call that reuses the same
+// buffer, so copy it if it must outlive that call. Pass the returned slice
+// back in as scratch to keep reusing the grown buffer.
+func (r *Resampler) ProcessReuse(input []int16, scratch []int16) []int16 {
+	if r.handle == nil || len(input) == 0 {
+		return scratch[:0]
+	}
+	need := r.maxOutput(len(input))
+	if cap(scratch) < need {
+		scratch = make([]int16, need)
+	}
+	return r.process(input, scratch[:need])
+}
+
+// SetDriftPPM nudges the conversion ratio by ppm parts per million to
+// absorb clock skew between capture and playback devices. Positive values
+// produce slightly more output per input sample (as if outRate were
+// outRate*(1+ppm/1e6)), negative values slightly less. The native resampler
+// glides to the new ratio, so it can be updated every block from a
+// buffer fill-level controller without clicks. Real clock skew is usually
+// within a few hundred ppm.
+func (r *Resampler) SetDriftPPM(ppm float32) {
+	if r.handle != nil {
+		C.voice_resampler_set_drift_ppm(r.handle, C.float(ppm))
+		r.driftPPM = ppm
+	}
+}
+
+// DriftPPM returns the drift correction last set with SetDriftPPM.
+func (r *Resampler) DriftPPM() float32 {
+	return r.driftPPM
+}
+
+// maxOutput returns the output buffer size needed for inputLen samples.
+func (r *Resampler) maxOutput(inputLen int) int {
+	outLen := (inputLen * r.outRate) / r.inRate
 	if outLen == 0 {
 		outLen = 1
 	}
-	output := make([]int16, outLen*2) // Extra space for edge cases
+	return outLen * 2 // Extra space for edge cases
+}
 
-	inLen := C.uint(len(input))
-	outLenC := C.uint(len(output))
+// process resamples input into output and returns the filled prefix.
+func (r *Resampler) process(input, output []int16) []int16 {
+	r.inLen = C.uint(len(input))
+	r.outLen = C.uint(len(output))
 	C.voice_resampler_process(r.handle,
-		(*C.short)(unsafe.Pointer(&input[0])), &inLen,
-		(*C.short)(unsafe.Pointer(&output[0])), &outLenC)
+		(*C.short)(unsafe.Pointer(&input[0])), &r.inLen,
+		(*C.short)(unsafe.Pointer(&output[0])), &r.outLen)
 
-	return output[:outLenC]
+	return output[:r.outLen]
+}
+
+// ErrNeedMoreInput is returned by ProcessFixed when not enough input has
+// accumulated to produce a full output frame.
+var ErrNeedMoreInput = errors.New("not enough input accumulated for a full frame")
+
+// ProcessFixed resamples input and returns exactly outFrame samples.
+//
+// Resampled output is buffered internally, hiding the fractional remainder of
+// non-integer ratios (e.g. 441 in / 480 out for 44.1kHz to 48kHz). If fewer
+// than outFrame samples are available, the input is still consumed and
+// ErrNeedMoreInput is returned; the samples are delivered by later calls.
+func (r *Resampler) ProcessFixed(input []int16, outFrame int) ([]int16, error) {
+	if r.handle == nil {
+		return nil, errors.New("resampler is closed")
+	}
+	if outFrame <= 0 {
+		return nil, errors.New("output frame size must be positive")
+	}
+	r.pending = append(r.pending, r.Process(input)...)
+	if len(r.pending) < outFrame {
+		return nil, ErrNeedMoreInput
+	}
+	output := make([]int16, outFrame)
+	copy(output, r.pending)
+	r.pending = r.pending[:copy(r.pending, r.pending[outFrame:])]
+	return output, nil
 }
 
 // Close releases the resampler resources.
@@ -312,23 +824,103 @@ func (r *Resampler) Close() error {
 	return nil
 }
 
+// ResamplerSNR measures the conversion quality of a Resampler setting. It
+// resamples a logarithmic sweep from inRate to outRate and back again with
+// the given quality, aligns the round trip with the original and returns the
+// signal-to-noise ratio in dB. Higher is better; a lossless round trip
+// returns +Inf. The sweep stays below 40% of the lower rate, so the result
+// reflects the filter's passband accuracy and aliasing rather than the loss
+// of content above the lower Nyquist frequency.
+//
+// It is intended for choosing a quality setting offline, not for use on a
+// real-time path. Returns 0 if a resampler cannot be created or the round
+// trip comes back too short to measure.
+func ResamplerSNR(inRate, outRate, quality int) float32 {
+	up, err := NewResampler(1, inRate, outRate, quality)
+	if err != nil {
+		return 0
+	}
+	defer up.Close()
+	down, err := NewResampler(1, outRate, inRate, quality)
+	if err != nil {
+		return 0
+	}
+	defer down.Close()
+
+	ref := Sweep(inRate, inRate/2, 50, 0.4*float32(min(inRate, outRate)), 0.5)
+	var trip []int16
+	block := max(1, inRate/100)
+	for start := 0; start < len(ref); start += block {
+		end := min(start+block, len(ref))
+		trip = append(trip, down.Process(up.Process(ref[start:end]))...)
+	}
+
+	// Skip the sweep edges, where the filters are still settling
+	from, to := len(ref)/10, len(ref)-len(ref)/10
+	if len(trip) <= from {
+		return 0
+	}
+	lag := bestLag(ref[from:to], trip[from:], inRate/50)
+	var signal, noise float64
+	for i := from; i < to && i+lag < len(trip); i++ {
+		d := float64(ref[i]) - float64(trip[i+lag])
+		signal += float64(ref[i]) * float64(ref[i])
+		noise += d * d
+	}
+	if noise == 0 {
+		return float32(math.Inf(1))
+	}
+	return float32(10 * math.Log10(signal/noise))
+}
+
+// bestLag returns the delay, from 0 to maxLag samples, at which b best
+// matches a.
+func bestLag(a, b []int16, maxLag int) int {
+	best, bestCorr := 0, math.Inf(-1)
+	for lag := 0; lag <= maxLag; lag++ {
+		var corr float64
+		for i := 0; i < len(a) && i+lag < len(b); i++ {
+			corr += float64(a[i]) * float64(b[i+lag])
+		}
+		if corr > bestCorr {
+			best, bestCorr = lag, corr
+		}
+	}
+	return best
+}
+
 // DtmfDetector detects DTMF tones in audio.
 type DtmfDetector struct {
-	handle    unsafe.Pointer
-	frameSize int
+	handle     unsafe.Pointer
+	sampleRate int
+	frameSize  int
 }
 
 // NewDtmfDetector creates a new DTMF tone detector.
 func NewDtmfDetector(sampleRate, frameSize int) (*DtmfDetector, error) {
+	if err := validate("DTMF detector", checkSampleRate(sampleRate), checkFrameSize(frameSize)); err != nil {
+		return nil, err
+	}
+	clearError()
 	handle := C.voice_dtmf_detector_create(C.int(sampleRate), C.int(frameSize))
 	if handle == nil {
-		return nil, errors.New("failed to create DTMF detector")
+		return nil, createError("DTMF detector")
 	}
-	d := &DtmfDetector{handle: handle, frameSize: frameSize}
+	d := &DtmfDetector{handle: handle, sampleRate: sampleRate, frameSize: frameSize}
 	runtime.SetFinalizer(d, (*DtmfDetector).Close)
 	return d, nil
 }
 
+// SampleRate returns the sample rate in Hz.
+func (d *DtmfDetector) SampleRate() int {
+	return d.sampleRate
+}
+
+// FrameSize returns the number of samples per frame.
+func (d *DtmfDetector) FrameSize() int {
+	return d.frameSize
+}
+
 // Process detects DTMF tones in the audio frame.
 // Returns the detected digit ('0'-'9', 'A'-'D', '*', '#') or 0 if none.
 func (d *DtmfDetector) Process(input []int16) byte {
@@ -358,15 +950,24 @@ type DtmfGenerator struct {
 
 // NewDtmfGenerator creates a new DTMF tone generator.
 func NewDtmfGenerator(sampleRate, toneDurationMs int) (*DtmfGenerator, error) {
+	if err := validate("DTMF generator", checkSampleRate(sampleRate)); err != nil {
+		return nil, err
+	}
+	clearError()
 	handle := C.voice_dtmf_generator_create(C.int(sampleRate), C.int(toneDurationMs))
 	if handle == nil {
-		return nil, errors.New("failed to create DTMF generator")
+		return nil, createError("DTMF generator")
 	}
 	g := &DtmfGenerator{handle: handle, sampleRate: sampleRate}
 	runtime.SetFinalizer(g, (*DtmfGenerator).Close)
 	return g, nil
 }
 
+// SampleRate returns the sample rate in Hz.
+func (g *DtmfGenerator) SampleRate() int {
+	return g.sampleRate
+}
+
 // Generate generates a single DTMF digit tone.
 func (g *DtmfGenerator) Generate(digit byte) []int16 {
 	if g.handle == nil {
@@ -408,21 +1009,59 @@ func (g *DtmfGenerator) Close() error {
 
 // Equalizer provides parametric equalization.
 type Equalizer struct {
-	handle unsafe.Pointer
-	bands  int
+	handle     unsafe.Pointer
+	bands      int
+	sampleRate int
+	settings   []eqBand
+}
+
+// eqBand holds the last settings applied to an equalizer band.
+type eqBand struct {
+	frequency, gain, q float32
 }
 
 // NewEqualizer creates a new parametric equalizer.
+//
+// The bands start flat (0dB) with centers spaced logarithmically from
+// 60Hz to 12kHz (or 90% of Nyquist, if lower).
 func NewEqualizer(sampleRate, numBands int) (*Equalizer, error) {
+	if err := validate("equalizer",
+		checkSampleRate(sampleRate),
+		checkRange("band count", float64(numBands), 1, math.MaxInt32),
+	); err != nil {
+		return nil, err
+	}
+	clearError()
 	handle := C.voice_equalizer_create(C.int(sampleRate), C.int(numBands))
 	if handle == nil {
-		return nil, errors.New("failed to create equalizer")
+		return nil, createError("equalizer")
+	}
+	e := &Equalizer{handle: handle, bands: numBands, sampleRate: sampleRate}
+	e.settings = make([]eqBand, numBands)
+	for band := range e.settings {
+		e.SetBand(band, defaultEqFrequency(sampleRate, band, numBands), 0, defaultEqQ)
 	}
-	e := &Equalizer{handle: handle, bands: numBands}
 	runtime.SetFinalizer(e, (*Equalizer).Close)
 	return e, nil
 }
 
+// SampleRate returns the sample rate in Hz.
+func (e *Equalizer) SampleRate() int {
+	return e.sampleRate
+}
+
+// defaultEqQ is the Q a band starts with.
+const defaultEqQ = 1.0
+
+// defaultEqFrequency returns the starting center frequency of a band.
+func defaultEqFrequency(sampleRate, band, numBands int) float32 {
+	lo, hi := 60.0, math.Min(12000, 0.45*float64(sampleRate))
+	if numBands <= 1 {
+		return float32(math.Sqrt(lo * hi))
+	}
+	return float32(lo * math.Pow(hi/lo, float64(band)/float64(numBands-1)))
+}
+
 // SetBand configures a specific equalizer band.
 //
 // Parameters:
@@ -434,6 +1073,7 @@ func (e *Equalizer) SetBand(band int, frequency, gain, q float32) {
 	if e.handle != nil && band >= 0 && band < e.bands {
 		C.voice_equalizer_set_band(e.handle, C.int(band),
 			C.float(frequency), C.float(gain), C.float(q))
+		e.settings[band] = eqBand{frequency: frequency, gain: gain, q: q}
 	}
 }
 
@@ -443,13 +1083,84 @@ func (e *Equalizer) Process(input []int16) []int16 {
 		return nil
 	}
 	output := make([]int16, len(input))
+	e.ProcessInto(output, input)
+	return output
+}
+
+// ProcessInto applies equalization to src, writing the result to dst without
+// allocating. It returns len(src), or 0 if dst is shorter than src or the
+// equalizer is closed.
+func (e *Equalizer) ProcessInto(dst, src []int16) int {
+	if e.handle == nil || len(src) == 0 || len(dst) < len(src) {
+		return 0
+	}
 	C.voice_equalizer_process(e.handle,
-		(*C.short)(unsafe.Pointer(&input[0])),
-		(*C.short)(unsafe.Pointer(&output[0])),
+		(*C.short)(unsafe.Pointer(&src[0])),
+		(*C.short)(unsafe.Pointer(&dst[0])),
+		C.int(len(src)))
+	return len(src)
+}
+
+// ProcessInPlace applies equalization directly to buf without allocating.
+//
+// The native equalizer runs its biquads sample by sample, so passing the same
+// buffer as input and output is safe. Equalizer and Compressor are the only
+// processors that currently support true in-place operation.
+func (e *Equalizer) ProcessInPlace(buf []int16) {
+	if e.handle == nil || len(buf) == 0 {
+		return
+	}
+	ptr := (*C.short)(unsafe.Pointer(&buf[0]))
+	C.voice_equalizer_process(e.handle, ptr, ptr, C.int(len(buf)))
+}
+
+// ProcessFloat applies equalization to -1..1 float audio.
+func (e *Equalizer) ProcessFloat(input []float32) []float32 {
+	if e.handle == nil || len(input) == 0 {
+		return nil
+	}
+	output := make([]float32, len(input))
+	C.voice_equalizer_process_float(e.handle,
+		(*C.float)(unsafe.Pointer(&input[0])),
+		(*C.float)(unsafe.Pointer(&output[0])),
+		C.int(len(input)))
+	return output
+}
+
+// Process32 applies equalization to 24-bit samples (see Int24Min).
+func (e *Equalizer) Process32(input []int32) []int32 {
+	if e.handle == nil || len(input) == 0 {
+		return nil
+	}
+	output := make([]int32, len(input))
+	C.voice_equalizer_process_s32(e.handle,
+		(*C.int)(unsafe.Pointer(&input[0])),
+		(*C.int)(unsafe.Pointer(&output[0])),
 		C.int(len(input)))
 	return output
 }
 
+// Clone returns an independent equalizer with the same sample rate and band
+// settings. Filter state is not copied; the clone starts from silence.
+func (e *Equalizer) Clone() (*Equalizer, error) {
+	if e.handle == nil {
+		return nil, errors.New("equalizer is closed")
+	}
+	clone, err := NewEqualizer(e.sampleRate, e.bands)
+	if err != nil {
+		return nil, err
+	}
+	for band, s := range e.settings {
+		clone.SetBand(band, s.frequency, s.gain, s.q)
+	}
+	return clone, nil
+}
+
+// Latency returns 0; the equalizer processes sample by sample.
+func (e *Equalizer) Latency() int {
+	return 0
+}
+
 // Close releases the equalizer resources.
 func (e *Equalizer) Close() error {
 	if e.handle != nil {
@@ -462,7 +1173,12 @@ func (e *Equalizer) Close() error {
 
 // Compressor provides dynamic range compression.
 type Compressor struct {
-	handle unsafe.Pointer
+	handle     unsafe.Pointer
+	sampleRate int
+	threshold  float32
+	ratio      float32
+	attackMs   float32
+	releaseMs  float32
 }
 
 // NewCompressor creates a new dynamic range compressor.
@@ -474,31 +1190,102 @@ type Compressor struct {
 //   - attackMs: Attack time in milliseconds
 //   - releaseMs: Release time in milliseconds
 func NewCompressor(sampleRate int, threshold, ratio, attackMs, releaseMs float32) (*Compressor, error) {
+	if err := validate("compressor", checkSampleRate(sampleRate)); err != nil {
+		return nil, err
+	}
+	clearError()
 	handle := C.voice_compressor_create(C.int(sampleRate),
 		C.float(threshold), C.float(ratio),
 		C.float(attackMs), C.float(releaseMs))
 	if handle == nil {
-		return nil, errors.New("failed to create compressor")
+		return nil, createError("compressor")
+	}
+	c := &Compressor{
+		handle:     handle,
+		sampleRate: sampleRate,
+		threshold:  threshold,
+		ratio:      ratio,
+		attackMs:   attackMs,
+		releaseMs:  releaseMs,
 	}
-	c := &Compressor{handle: handle}
 	runtime.SetFinalizer(c, (*Compressor).Close)
 	return c, nil
 }
 
+// SampleRate returns the sample rate in Hz.
+func (c *Compressor) SampleRate() int {
+	return c.sampleRate
+}
+
+// SetThreshold sets the compression threshold in dB.
+func (c *Compressor) SetThreshold(threshold float32) {
+	c.threshold = threshold
+	c.applyParams()
+}
+
+// SetRatio sets the compression ratio (e.g., 4.0 for 4:1).
+func (c *Compressor) SetRatio(ratio float32) {
+	c.ratio = ratio
+	c.applyParams()
+}
+
+// SetAttack sets the attack time in milliseconds.
+func (c *Compressor) SetAttack(ms float32) {
+	c.attackMs = ms
+	c.applyParams()
+}
+
+// SetRelease sets the release time in milliseconds.
+func (c *Compressor) SetRelease(ms float32) {
+	c.releaseMs = ms
+	c.applyParams()
+}
+
+func (c *Compressor) applyParams() {
+	if c.handle != nil {
+		C.voice_compressor_set_params(c.handle,
+			C.float(c.threshold), C.float(c.ratio),
+			C.float(c.attackMs), C.float(c.releaseMs))
+	}
+}
+
 // Process applies compression to the audio.
 func (c *Compressor) Process(input []int16) []int16 {
 	if c.handle == nil || len(input) == 0 {
 		return nil
 	}
 	output := make([]int16, len(input))
-	C.voice_compressor_process(c.handle,
-		(*C.short)(unsafe.Pointer(&input[0])),
-		(*C.short)(unsafe.Pointer(&output[0])),
-		C.int(len(input)))
+	c.ProcessInto(output, input)
 	return output
 }
 
-// GetGainReduction returns the current gain reduction in dB.
+// ProcessInto applies compression to src, writing the result to dst without
+// allocating. It returns len(src), or 0 if dst is shorter than src or the
+// compressor is closed.
+func (c *Compressor) ProcessInto(dst, src []int16) int {
+	if c.handle == nil || len(src) == 0 || len(dst) < len(src) {
+		return 0
+	}
+	C.voice_compressor_process(c.handle,
+		(*C.short)(unsafe.Pointer(&src[0])),
+		(*C.short)(unsafe.Pointer(&dst[0])),
+		C.int(len(src)))
+	return len(src)
+}
+
+// ProcessInPlace applies compression directly to buf without allocating.
+//
+// The native compressor computes gain per sample and applies it to the same
+// sample, so passing the same buffer as input and output is safe.
+func (c *Compressor) ProcessInPlace(buf []int16) {
+	if c.handle == nil || len(buf) == 0 {
+		return
+	}
+	ptr := (*C.short)(unsafe.Pointer(&buf[0]))
+	C.voice_compressor_process(c.handle, ptr, ptr, C.int(len(buf)))
+}
+
+// GetGainReduction returns the current gain reduction in dB.
 func (c *Compressor) GetGainReduction() float32 {
 	if c.handle == nil {
 		return 0
@@ -506,6 +1293,46 @@ func (c *Compressor) GetGainReduction() float32 {
 	return float32(C.voice_compressor_get_gain_reduction(c.handle))
 }
 
+// ProcessFloat applies compression to -1..1 float audio.
+func (c *Compressor) ProcessFloat(input []float32) []float32 {
+	if c.handle == nil || len(input) == 0 {
+		return nil
+	}
+	output := make([]float32, len(input))
+	C.voice_compressor_process_float(c.handle,
+		(*C.float)(unsafe.Pointer(&input[0])),
+		(*C.float)(unsafe.Pointer(&output[0])),
+		C.int(len(input)))
+	return output
+}
+
+// Process32 applies compression to 24-bit samples (see Int24Min).
+func (c *Compressor) Process32(input []int32) []int32 {
+	if c.handle == nil || len(input) == 0 {
+		return nil
+	}
+	output := make([]int32, len(input))
+	C.voice_compressor_process_s32(c.handle,
+		(*C.int)(unsafe.Pointer(&input[0])),
+		(*C.int)(unsafe.Pointer(&output[0])),
+		C.int(len(input)))
+	return output
+}
+
+// Clone returns an independent compressor with the same settings. Envelope
+// state is not copied; the clone starts with no gain reduction.
+func (c *Compressor) Clone() (*Compressor, error) {
+	if c.handle == nil {
+		return nil, errors.New("compressor is closed")
+	}
+	return NewCompressor(c.sampleRate, c.threshold, c.ratio, c.attackMs, c.releaseMs)
+}
+
+// Latency returns 0; the compressor does not use look-ahead.
+func (c *Compressor) Latency() int {
+	return 0
+}
+
 // Close releases the compressor resources.
 func (c *Compressor) Close() error {
 	if c.handle != nil {
@@ -516,22 +1343,216 @@ func (c *Compressor) Close() error {
 	return nil
 }
 
+// MultibandCompressor applies independent dynamic range compression to
+// frequency bands split at configurable crossover frequencies.
+type MultibandCompressor struct {
+	handle     unsafe.Pointer
+	sampleRate int
+	bands      int
+}
+
+// NewMultibandCompressor creates a new multiband compressor with
+// len(crossovers)+1 bands.
+//
+// Parameters:
+//   - sampleRate: Audio sample rate in Hz
+//   - crossovers: Ascending crossover frequencies in Hz
+func NewMultibandCompressor(sampleRate int, crossovers []float32) (*MultibandCompressor, error) {
+	if err := validate("multiband compressor", checkSampleRate(sampleRate)); err != nil {
+		return nil, err
+	}
+	var freqs *C.float
+	if len(crossovers) > 0 {
+		freqs = (*C.float)(unsafe.Pointer(&crossovers[0]))
+	}
+	clearError()
+	handle := C.voice_mbcomp_create(C.int(sampleRate), freqs, C.int(len(crossovers)))
+	if handle == nil {
+		return nil, createError("multiband compressor")
+	}
+	m := &MultibandCompressor{handle: handle, sampleRate: sampleRate, bands: len(crossovers) + 1}
+	runtime.SetFinalizer(m, (*MultibandCompressor).Close)
+	return m, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (m *MultibandCompressor) SampleRate() int {
+	return m.sampleRate
+}
+
+// Bands returns the number of frequency bands.
+func (m *MultibandCompressor) Bands() int {
+	return m.bands
+}
+
+// SetBand configures the compressor for a band.
+//
+// Parameters:
+//   - band: Band index (0 to Bands()-1, lowest frequency first)
+//   - threshold: Compression threshold in dB
+//   - ratio: Compression ratio (e.g., 4.0 for 4:1)
+//   - attackMs: Attack time in milliseconds
+//   - releaseMs: Release time in milliseconds
+func (m *MultibandCompressor) SetBand(band int, threshold, ratio, attackMs, releaseMs float32) {
+	if m.handle != nil && band >= 0 && band < m.bands {
+		C.voice_mbcomp_set_band(m.handle, C.int(band),
+			C.float(threshold), C.float(ratio),
+			C.float(attackMs), C.float(releaseMs))
+	}
+}
+
+// Process applies multiband compression to the audio.
+func (m *MultibandCompressor) Process(input []int16) []int16 {
+	if m.handle == nil || len(input) == 0 {
+		return nil
+	}
+	output := make([]int16, len(input))
+	m.ProcessInto(output, input)
+	return output
+}
+
+// ProcessInto applies multiband compression to src, writing the result to
+// dst without allocating. It returns len(src), or 0 if dst is shorter than
+// src or the compressor is closed.
+func (m *MultibandCompressor) ProcessInto(dst, src []int16) int {
+	if m.handle == nil || len(src) == 0 || len(dst) < len(src) {
+		return 0
+	}
+	C.voice_mbcomp_process(m.handle,
+		(*C.short)(unsafe.Pointer(&src[0])),
+		(*C.short)(unsafe.Pointer(&dst[0])),
+		C.int(len(src)))
+	return len(src)
+}
+
+// GainReduction returns the current gain reduction in dB for a band.
+func (m *MultibandCompressor) GainReduction(band int) float32 {
+	if m.handle == nil || band < 0 || band >= m.bands {
+		return 0
+	}
+	return float32(C.voice_mbcomp_get_gain_reduction(m.handle, C.int(band)))
+}
+
+// Close releases the multiband compressor resources.
+func (m *MultibandCompressor) Close() error {
+	if m.handle != nil {
+		C.voice_mbcomp_destroy(m.handle)
+		m.handle = nil
+		runtime.SetFinalizer(m, nil)
+	}
+	return nil
+}
+
+// Crossover splits audio into frequency bands with Linkwitz-Riley (4th
+// order) filters, as a building block for multiband effects. The bands sum
+// to an all-pass version of the input: the magnitude response is flat, with
+// some phase shift around each crossover frequency.
+type Crossover struct {
+	handle     unsafe.Pointer
+	sampleRate int
+	bands      int
+}
+
+// NewCrossover creates a new crossover with len(frequencies)+1 bands.
+//
+// Parameters:
+//   - sampleRate: Audio sample rate in Hz
+//   - frequencies: Ascending crossover frequencies in Hz, below Nyquist
+func NewCrossover(sampleRate int, frequencies []float32) (*Crossover, error) {
+	if err := validate("crossover", checkSampleRate(sampleRate)); err != nil {
+		return nil, err
+	}
+	if len(frequencies) == 0 {
+		return nil, errors.New("failed to create crossover: no crossover frequencies")
+	}
+	// Each frequency must lie strictly between the one before (or 0) and
+	// Nyquist, or the band between them is empty
+	lo, nyquist := 0.0, float64(sampleRate)/2
+	for _, f := range frequencies {
+		if !(float64(f) > lo && float64(f) < nyquist) {
+			return nil, fmt.Errorf("failed to create crossover: %w: crossover frequency %g not in (%g, %g)",
+				ErrInvalidRange, f, lo, nyquist)
+		}
+		lo = float64(f)
+	}
+	clearError()
+	handle := C.voice_crossover_create(C.int(sampleRate),
+		(*C.float)(unsafe.Pointer(&frequencies[0])),
+		C.int(len(frequencies)))
+	if handle == nil {
+		return nil, createError("crossover")
+	}
+	c := &Crossover{handle: handle, sampleRate: sampleRate, bands: len(frequencies) + 1}
+	runtime.SetFinalizer(c, (*Crossover).Close)
+	return c, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (c *Crossover) SampleRate() int {
+	return c.sampleRate
+}
+
+// Bands returns the number of frequency bands.
+func (c *Crossover) Bands() int {
+	return c.bands
+}
+
+// Split returns Bands() slices, each len(input) samples long, holding the
+// input's content in each band, lowest frequency first.
+func (c *Crossover) Split(input []int16) [][]int16 {
+	if c.handle == nil || len(input) == 0 {
+		return nil
+	}
+	// The native side writes the bands one after another into a single
+	// buffer
+	flat := make([]int16, c.bands*len(input))
+	C.voice_crossover_process(c.handle,
+		(*C.short)(unsafe.Pointer(&input[0])),
+		C.int(len(input)),
+		(*C.short)(unsafe.Pointer(&flat[0])))
+	bands := make([][]int16, c.bands)
+	for b := range bands {
+		bands[b] = flat[b*len(input) : (b+1)*len(input) : (b+1)*len(input)]
+	}
+	return bands
+}
+
+// Close releases the crossover resources.
+func (c *Crossover) Close() error {
+	if c.handle != nil {
+		C.voice_crossover_destroy(c.handle)
+		c.handle = nil
+		runtime.SetFinalizer(c, nil)
+	}
+	return nil
+}
+
 // ComfortNoiseGenerator generates comfort noise.
 type ComfortNoiseGenerator struct {
-	handle unsafe.Pointer
+	handle     unsafe.Pointer
+	sampleRate int
 }
 
 // NewComfortNoiseGenerator creates a new comfort noise generator.
 func NewComfortNoiseGenerator(sampleRate int, level float32) (*ComfortNoiseGenerator, error) {
+	if err := validate("CNG", checkSampleRate(sampleRate)); err != nil {
+		return nil, err
+	}
+	clearError()
 	handle := C.voice_cng_create(C.int(sampleRate), C.float(level))
 	if handle == nil {
-		return nil, errors.New("failed to create CNG")
+		return nil, createError("CNG")
 	}
-	c := &ComfortNoiseGenerator{handle: handle}
+	c := &ComfortNoiseGenerator{handle: handle, sampleRate: sampleRate}
 	runtime.SetFinalizer(c, (*ComfortNoiseGenerator).Close)
 	return c, nil
 }
 
+// SampleRate returns the sample rate in Hz.
+func (c *ComfortNoiseGenerator) SampleRate() int {
+	return c.sampleRate
+}
+
 // Generate generates comfort noise samples.
 func (c *ComfortNoiseGenerator) Generate(numSamples int) []int16 {
 	if c.handle == nil || numSamples <= 0 {
@@ -560,3 +1581,821 @@ func (c *ComfortNoiseGenerator) Close() error {
 	}
 	return nil
 }
+
+// SetDitherSeed seeds the random generators so that runs are reproducible,
+// for example in bit-exact regression tests. The same seed followed by the
+// same calls yields identical output.
+//
+// The generators are process-wide. They drive the noise of
+// ComfortNoiseGenerator and DtxDecoder and the dither of
+// Float32ToInt16Dithered (and so of ProcessFloat with a dither set), which
+// are the only sources of randomness; everything else is deterministic, so
+// a fresh instance given the same input always produces the same output.
+// Because the generators are shared, noise sources running concurrently
+// draw from the same sequence and are only reproducible when called in the
+// same order.
+func SetDitherSeed(seed uint64) {
+	C.voice_cng_set_seed(C.uint32_t(uint32(seed ^ seed>>32)))
+	ditherMu.Lock()
+	ditherRand.Seed(int64(seed))
+	ditherMu.Unlock()
+}
+
+// FeedbackSuppressor detects acoustic feedback (ringing) and removes it with
+// dynamically placed notch filters.
+type FeedbackSuppressor struct {
+	handle     unsafe.Pointer
+	sampleRate int
+	maxNotches int
+}
+
+// NewFeedbackSuppressor creates a new feedback suppressor.
+//
+// Parameters:
+//   - sampleRate: Audio sample rate in Hz
+//   - maxNotches: Maximum number of simultaneous notch filters
+func NewFeedbackSuppressor(sampleRate, maxNotches int) (*FeedbackSuppressor, error) {
+	if err := validate("feedback suppressor", checkSampleRate(sampleRate)); err != nil {
+		return nil, err
+	}
+	clearError()
+	handle := C.voice_feedback_create(C.int(sampleRate), C.int(maxNotches))
+	if handle == nil {
+		return nil, createError("feedback suppressor")
+	}
+	f := &FeedbackSuppressor{handle: handle, sampleRate: sampleRate, maxNotches: maxNotches}
+	runtime.SetFinalizer(f, (*FeedbackSuppressor).Close)
+	return f, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (f *FeedbackSuppressor) SampleRate() int {
+	return f.sampleRate
+}
+
+// Process detects narrowband peaks and applies notch filters to the audio.
+func (f *FeedbackSuppressor) Process(input []int16) []int16 {
+	if f.handle == nil || len(input) == 0 {
+		return nil
+	}
+	output := make([]int16, len(input))
+	f.ProcessInto(output, input)
+	return output
+}
+
+// ProcessInto detects narrowband peaks and applies notch filters to src,
+// writing the result to dst without allocating. It returns len(src), or 0 if
+// dst is shorter than src or the suppressor is closed.
+func (f *FeedbackSuppressor) ProcessInto(dst, src []int16) int {
+	if f.handle == nil || len(src) == 0 || len(dst) < len(src) {
+		return 0
+	}
+	C.voice_feedback_process(f.handle,
+		(*C.short)(unsafe.Pointer(&src[0])),
+		(*C.short)(unsafe.Pointer(&dst[0])),
+		C.int(len(src)))
+	return len(src)
+}
+
+// ActiveNotches returns the center frequencies in Hz of the notch filters
+// currently in use.
+func (f *FeedbackSuppressor) ActiveNotches() []float32 {
+	if f.handle == nil || f.maxNotches <= 0 {
+		return nil
+	}
+	notches := make([]float32, f.maxNotches)
+	count := C.voice_feedback_get_notches(f.handle,
+		(*C.float)(unsafe.Pointer(&notches[0])),
+		C.int(len(notches)))
+	return notches[:count]
+}
+
+// Close releases the feedback suppressor resources.
+func (f *FeedbackSuppressor) Close() error {
+	if f.handle != nil {
+		C.voice_feedback_destroy(f.handle)
+		f.handle = nil
+		runtime.SetFinalizer(f, nil)
+	}
+	return nil
+}
+
+// TempoDetector estimates musical tempo from onset statistics accumulated
+// across calls.
+type TempoDetector struct {
+	handle     unsafe.Pointer
+	sampleRate int
+}
+
+// NewTempoDetector creates a new tempo detector.
+func NewTempoDetector(sampleRate int) (*TempoDetector, error) {
+	if err := validate("tempo detector", checkSampleRate(sampleRate)); err != nil {
+		return nil, err
+	}
+	clearError()
+	handle := C.voice_tempo_create(C.int(sampleRate))
+	if handle == nil {
+		return nil, createError("tempo detector")
+	}
+	t := &TempoDetector{handle: handle, sampleRate: sampleRate}
+	runtime.SetFinalizer(t, (*TempoDetector).Close)
+	return t, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (t *TempoDetector) SampleRate() int {
+	return t.sampleRate
+}
+
+// Process analyzes the audio and returns the current tempo estimate in
+// beats per minute with a confidence score (0.0-1.0).
+func (t *TempoDetector) Process(input []int16) (bpm float32, confidence float32) {
+	if t.handle == nil || len(input) == 0 {
+		return 0, 0
+	}
+	var cBpm, cConf C.float
+	C.voice_tempo_process(t.handle,
+		(*C.short)(unsafe.Pointer(&input[0])),
+		C.int(len(input)),
+		&cBpm, &cConf)
+	return float32(cBpm), float32(cConf)
+}
+
+// Reset discards the accumulated onset statistics.
+func (t *TempoDetector) Reset() {
+	if t.handle != nil {
+		C.voice_tempo_reset(t.handle)
+	}
+}
+
+// Close releases the tempo detector resources.
+func (t *TempoDetector) Close() error {
+	if t.handle != nil {
+		C.voice_tempo_destroy(t.handle)
+		t.handle = nil
+		runtime.SetFinalizer(t, nil)
+	}
+	return nil
+}
+
+// OnsetDetector finds note onsets and other transients, such as drum hits,
+// for triggering effects in time with the audio.
+type OnsetDetector struct {
+	handle     unsafe.Pointer
+	sampleRate int
+	offsets    []C.int
+}
+
+// NewOnsetDetector creates a new onset detector with a sensitivity of 0.5.
+func NewOnsetDetector(sampleRate int) (*OnsetDetector, error) {
+	if err := validate("onset detector", checkSampleRate(sampleRate)); err != nil {
+		return nil, err
+	}
+	clearError()
+	handle := C.voice_onset_create(C.int(sampleRate))
+	if handle == nil {
+		return nil, createError("onset detector")
+	}
+	o := &OnsetDetector{handle: handle, sampleRate: sampleRate}
+	runtime.SetFinalizer(o, (*OnsetDetector).Close)
+	return o, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (o *OnsetDetector) SampleRate() int {
+	return o.sampleRate
+}
+
+// SetSensitivity sets how readily transients are reported, from 0.0 (only
+// the sharpest attacks) to 1.0 (subtle changes too).
+func (o *OnsetDetector) SetSensitivity(sensitivity float32) {
+	if o.handle != nil {
+		C.voice_onset_set_sensitivity(o.handle, C.float(sensitivity))
+	}
+}
+
+// Process analyzes the audio and returns the sample offsets within input of
+// the onsets detected in it, in increasing order. Detection state carries
+// over between calls, so an onset on a block boundary is reported once.
+func (o *OnsetDetector) Process(input []int16) []int {
+	if o.handle == nil || len(input) == 0 {
+		return nil
+	}
+	if cap(o.offsets) < len(input) {
+		o.offsets = make([]C.int, len(input))
+	}
+	n := int(C.voice_onset_process(o.handle,
+		(*C.short)(unsafe.Pointer(&input[0])),
+		C.int(len(input)),
+		&o.offsets[0],
+		C.int(len(input))))
+	if n <= 0 {
+		return nil
+	}
+	onsets := make([]int, n)
+	for i := range onsets {
+		onsets[i] = int(o.offsets[i])
+	}
+	return onsets
+}
+
+// Close releases the onset detector resources.
+func (o *OnsetDetector) Close() error {
+	if o.handle != nil {
+		C.voice_onset_destroy(o.handle)
+		o.handle = nil
+		runtime.SetFinalizer(o, nil)
+	}
+	return nil
+}
+
+// PitchDetector estimates the fundamental frequency (f0) of monophonic
+// audio such as a voice or a single instrument, using the YIN algorithm.
+type PitchDetector struct {
+	handle     unsafe.Pointer
+	sampleRate int
+}
+
+// NewPitchDetector creates a new pitch detector.
+//
+// Parameters:
+//   - sampleRate: Audio sample rate in Hz
+//   - minHz: Lowest fundamental to search for
+//   - maxHz: Highest fundamental to search for
+func NewPitchDetector(sampleRate int, minHz, maxHz float32) (*PitchDetector, error) {
+	if err := validate("pitch detector",
+		checkSampleRate(sampleRate),
+		checkRange("minimum frequency", float64(minHz), 1, float64(maxHz)),
+		checkRange("maximum frequency", float64(maxHz), float64(minHz), float64(sampleRate)/2),
+	); err != nil {
+		return nil, err
+	}
+	clearError()
+	handle := C.voice_pitch_detect_create(C.int(sampleRate), C.float(minHz), C.float(maxHz))
+	if handle == nil {
+		return nil, createError("pitch detector")
+	}
+	p := &PitchDetector{handle: handle, sampleRate: sampleRate}
+	runtime.SetFinalizer(p, (*PitchDetector).Close)
+	return p, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (p *PitchDetector) SampleRate() int {
+	return p.sampleRate
+}
+
+// Process estimates the fundamental of the block in Hz, with a confidence
+// score (0.0-1.0). Unvoiced or silent input yields 0 for both. The block
+// should span at least two periods of the lowest frequency searched.
+func (p *PitchDetector) Process(input []int16) (hz float32, confidence float32) {
+	if p.handle == nil || len(input) == 0 {
+		return 0, 0
+	}
+	var cHz, cConf C.float
+	C.voice_pitch_detect_process(p.handle,
+		(*C.short)(unsafe.Pointer(&input[0])),
+		C.int(len(input)),
+		&cHz, &cConf)
+	if cHz <= 0 {
+		return 0, 0
+	}
+	return float32(cHz), float32(cConf)
+}
+
+// Close releases the pitch detector resources.
+func (p *PitchDetector) Close() error {
+	if p.handle != nil {
+		C.voice_pitch_detect_destroy(p.handle)
+		p.handle = nil
+		runtime.SetFinalizer(p, nil)
+	}
+	return nil
+}
+
+// Fingerprinter computes a compact acoustic fingerprint of audio, for
+// recognizing the same content across streams or files. Unlike a watermark
+// nothing is embedded: the fingerprint is derived from the audio itself and
+// survives moderate noise, level changes and lossy coding.
+type Fingerprinter struct {
+	handle     unsafe.Pointer
+	sampleRate int
+}
+
+// NewFingerprinter creates a new fingerprinter.
+func NewFingerprinter(sampleRate int) (*Fingerprinter, error) {
+	if err := validate("fingerprinter", checkSampleRate(sampleRate)); err != nil {
+		return nil, err
+	}
+	clearError()
+	handle := C.voice_fingerprint_create(C.int(sampleRate))
+	if handle == nil {
+		return nil, createError("fingerprinter")
+	}
+	f := &Fingerprinter{handle: handle, sampleRate: sampleRate}
+	runtime.SetFinalizer(f, (*Fingerprinter).Close)
+	return f, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (f *Fingerprinter) SampleRate() int {
+	return f.sampleRate
+}
+
+// Process adds audio to the fingerprint. Audio may be passed in blocks of
+// any length.
+func (f *Fingerprinter) Process(input []int16) {
+	if f.handle == nil || len(input) == 0 {
+		return
+	}
+	C.voice_fingerprint_process(f.handle,
+		(*C.short)(unsafe.Pointer(&input[0])),
+		C.int(len(input)))
+}
+
+// Fingerprint returns the fingerprint of all audio processed so far, one
+// 32-bit sub-fingerprint per analysis frame. Compare fingerprints with
+// MatchFingerprints.
+func (f *Fingerprinter) Fingerprint() []uint32 {
+	if f.handle == nil {
+		return nil
+	}
+	n := int(C.voice_fingerprint_length(f.handle))
+	if n == 0 {
+		return nil
+	}
+	hashes := make([]uint32, n)
+	n = int(C.voice_fingerprint_get(f.handle,
+		(*C.uint)(unsafe.Pointer(&hashes[0])),
+		C.int(len(hashes))))
+	return hashes[:n]
+}
+
+// Reset discards the accumulated fingerprint.
+func (f *Fingerprinter) Reset() {
+	if f.handle != nil {
+		C.voice_fingerprint_reset(f.handle)
+	}
+}
+
+// Close releases the fingerprinter resources.
+func (f *Fingerprinter) Close() error {
+	if f.handle != nil {
+		C.voice_fingerprint_destroy(f.handle)
+		f.handle = nil
+		runtime.SetFinalizer(f, nil)
+	}
+	return nil
+}
+
+// MatchFingerprints returns the similarity of two fingerprints, from 0.0
+// to 1.0, as the fraction of matching bits. The fingerprints are compared
+// at every alignment that overlaps at least half of the shorter one, so
+// recordings need not start at the same point; the best alignment wins.
+// Unrelated audio scores around 0.5 and the same audio close to 1.0.
+func MatchFingerprints(a, b []uint32) float32 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	minOverlap := (min(len(a), len(b)) + 1) / 2
+	best := 0.0
+	for shift := -(len(b) - minOverlap); shift <= len(a)-minOverlap; shift++ {
+		matching, total := 0, 0
+		for i := max(0, shift); i < len(a) && i-shift < len(b); i++ {
+			matching += 32 - bits.OnesCount32(a[i]^b[i-shift])
+			total += 32
+		}
+		if score := float64(matching) / float64(total); score > best {
+			best = score
+		}
+	}
+	return float32(best)
+}
+
+// ClickRemover detects sample-level clicks and pops and replaces them with
+// interpolated audio.
+type ClickRemover struct {
+	handle     unsafe.Pointer
+	sampleRate int
+}
+
+// NewClickRemover creates a new click remover.
+func NewClickRemover(sampleRate int) (*ClickRemover, error) {
+	if err := validate("click remover", checkSampleRate(sampleRate)); err != nil {
+		return nil, err
+	}
+	clearError()
+	handle := C.voice_declick_create(C.int(sampleRate))
+	if handle == nil {
+		return nil, createError("click remover")
+	}
+	c := &ClickRemover{handle: handle, sampleRate: sampleRate}
+	runtime.SetFinalizer(c, (*ClickRemover).Close)
+	return c, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (c *ClickRemover) SampleRate() int {
+	return c.sampleRate
+}
+
+// Process removes clicks from the audio.
+func (c *ClickRemover) Process(input []int16) []int16 {
+	if c.handle == nil || len(input) == 0 {
+		return nil
+	}
+	output := make([]int16, len(input))
+	c.ProcessInto(output, input)
+	return output
+}
+
+// ProcessInto removes clicks from src, writing the result to dst without
+// allocating. It returns len(src), or 0 if dst is shorter than src or the
+// click remover is closed.
+func (c *ClickRemover) ProcessInto(dst, src []int16) int {
+	if c.handle == nil || len(src) == 0 || len(dst) < len(src) {
+		return 0
+	}
+	C.voice_declick_process(c.handle,
+		(*C.short)(unsafe.Pointer(&src[0])),
+		(*C.short)(unsafe.Pointer(&dst[0])),
+		C.int(len(src)))
+	return len(src)
+}
+
+// Close releases the click remover resources.
+func (c *ClickRemover) Close() error {
+	if c.handle != nil {
+		C.voice_declick_destroy(c.handle)
+		c.handle = nil
+		runtime.SetFinalizer(c, nil)
+	}
+	return nil
+}
+
+// SpectralGate removes steady background noise, such as hiss, hum or air
+// conditioning, by attenuating each frequency bin that stays below a noise
+// profile learned from a noise-only passage.
+type SpectralGate struct {
+	handle     unsafe.Pointer
+	sampleRate int
+	fftSize    int
+}
+
+// NewSpectralGate creates a new spectral gate. Until Learn is called it
+// passes audio through unchanged, apart from its latency.
+//
+// Parameters:
+//   - sampleRate: Audio sample rate in Hz
+//   - fftSize: Analysis frame length, a power of two from 256 to 8192;
+//     larger sizes resolve tonal noise better but smear transients
+func NewSpectralGate(sampleRate, fftSize int) (*SpectralGate, error) {
+	if err := validate("spectral gate",
+		checkSampleRate(sampleRate),
+		checkRange("FFT size", float64(fftSize), 256, 8192),
+		checkPowerOfTwo("FFT size", fftSize),
+	); err != nil {
+		return nil, err
+	}
+	clearError()
+	handle := C.voice_specgate_create(C.int(sampleRate), C.int(fftSize))
+	if handle == nil {
+		return nil, createError("spectral gate")
+	}
+	g := &SpectralGate{handle: handle, sampleRate: sampleRate, fftSize: fftSize}
+	runtime.SetFinalizer(g, (*SpectralGate).Close)
+	return g, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (g *SpectralGate) SampleRate() int {
+	return g.sampleRate
+}
+
+// Learn captures the noise profile from a passage containing only the
+// noise to remove, such as the room tone before speech starts. At least
+// fftSize samples are needed; a second or more gives a steadier profile.
+// Calling Learn again replaces the profile.
+func (g *SpectralGate) Learn(noise []int16) {
+	if g.handle == nil || len(noise) == 0 {
+		return
+	}
+	C.voice_specgate_learn(g.handle,
+		(*C.short)(unsafe.Pointer(&noise[0])),
+		C.int(len(noise)))
+}
+
+// SetReductionDb sets how far bins below the noise profile are attenuated,
+// in dB (positive values). The default is 12dB; beyond about 20dB the
+// residual noise takes on a watery, musical quality.
+func (g *SpectralGate) SetReductionDb(db float32) {
+	if g.handle != nil {
+		C.voice_specgate_set_reduction(g.handle, C.float(db))
+	}
+}
+
+// Process applies noise reduction to the audio.
+func (g *SpectralGate) Process(input []int16) []int16 {
+	if g.handle == nil || len(input) == 0 {
+		return nil
+	}
+	output := make([]int16, len(input))
+	g.ProcessInto(output, input)
+	return output
+}
+
+// ProcessInto applies noise reduction to src, writing the result to dst
+// without allocating. It returns len(src), or 0 if dst is shorter than src
+// or the gate is closed.
+func (g *SpectralGate) ProcessInto(dst, src []int16) int {
+	if g.handle == nil || len(src) == 0 || len(dst) < len(src) {
+		return 0
+	}
+	C.voice_specgate_process(g.handle,
+		(*C.short)(unsafe.Pointer(&src[0])),
+		(*C.short)(unsafe.Pointer(&dst[0])),
+		C.int(len(src)))
+	return len(src)
+}
+
+// Latency returns the processing delay in samples, one FFT frame.
+func (g *SpectralGate) Latency() int {
+	return g.fftSize
+}
+
+// Close releases the spectral gate resources.
+func (g *SpectralGate) Close() error {
+	if g.handle != nil {
+		C.voice_specgate_destroy(g.handle)
+		g.handle = nil
+		runtime.SetFinalizer(g, nil)
+	}
+	return nil
+}
+
+// WindowType selects the window SpectrumAnalyzer applies before the FFT.
+type WindowType int
+
+const (
+	// WindowRectangular applies no window. It resolves tones exactly on a
+	// bin best but leaks heavily for anything in between.
+	WindowRectangular WindowType = 0
+	// WindowHann is a good general-purpose window, with sidelobes falling
+	// off quickly away from a tone.
+	WindowHann WindowType = 1
+	// WindowHamming has a lower first sidelobe than Hann (-43dB) but a
+	// flatter floor further out.
+	WindowHamming WindowType = 2
+	// WindowBlackman trades a wider main lobe for sidelobes below -58dB,
+	// for picking out quiet tones next to loud ones.
+	WindowBlackman WindowType = 3
+)
+
+// SpectrumAnalyzer computes magnitude spectra for visualization and
+// debugging.
+type SpectrumAnalyzer struct {
+	handle     unsafe.Pointer
+	sampleRate int
+	fftSize    int
+}
+
+// NewSpectrumAnalyzer creates a new spectrum analyzer.
+//
+// Parameters:
+//   - sampleRate: Audio sample rate in Hz
+//   - fftSize: Analysis length, a power of two from 64 to 16384; the bins
+//     are sampleRate/fftSize Hz apart
+//   - window: Window applied to the input before the FFT
+func NewSpectrumAnalyzer(sampleRate, fftSize int, window WindowType) (*SpectrumAnalyzer, error) {
+	if err := validate("spectrum analyzer",
+		checkPositiveRate(sampleRate),
+		checkRange("FFT size", float64(fftSize), 64, 16384),
+		checkPowerOfTwo("FFT size", fftSize),
+		checkRange("window", float64(window), float64(WindowRectangular), float64(WindowBlackman)),
+	); err != nil {
+		return nil, err
+	}
+	clearError()
+	handle := C.voice_fft_create(C.int(fftSize), C.int(window))
+	if handle == nil {
+		return nil, createError("spectrum analyzer")
+	}
+	s := &SpectrumAnalyzer{handle: handle, sampleRate: sampleRate, fftSize: fftSize}
+	runtime.SetFinalizer(s, (*SpectrumAnalyzer).Close)
+	return s, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (s *SpectrumAnalyzer) SampleRate() int {
+	return s.sampleRate
+}
+
+// FrameSize returns the FFT size, the number of samples analyzed per call.
+func (s *SpectrumAnalyzer) FrameSize() int {
+	return s.fftSize
+}
+
+// Process returns the magnitude spectrum of the first fftSize samples of
+// input, zero-padded if input is shorter. There are fftSize/2+1 bins, from
+// DC to the Nyquist frequency, in dB relative to full scale: a full-scale
+// sine centered on a bin reads 0dB in that bin, whatever the window.
+func (s *SpectrumAnalyzer) Process(input []int16) []float32 {
+	if s.handle == nil {
+		return nil
+	}
+	frame := input
+	if len(frame) < s.fftSize {
+		frame = make([]int16, s.fftSize)
+		copy(frame, input)
+	}
+	output := make([]float32, s.fftSize/2+1)
+	C.voice_fft_magnitude_db(s.handle,
+		(*C.short)(unsafe.Pointer(&frame[0])),
+		(*C.float)(unsafe.Pointer(&output[0])))
+	return output
+}
+
+// BinToHz returns the center frequency of a bin in Hz.
+func (s *SpectrumAnalyzer) BinToHz(bin int) float32 {
+	return float32(bin) * float32(s.sampleRate) / float32(s.fftSize)
+}
+
+// Close releases the spectrum analyzer resources.
+func (s *SpectrumAnalyzer) Close() error {
+	if s.handle != nil {
+		C.voice_fft_destroy(s.handle)
+		s.handle = nil
+		runtime.SetFinalizer(s, nil)
+	}
+	return nil
+}
+
+// DtxSIDSize is the length of the silence insertion descriptor (SID) payload
+// emitted by DtxEncoder during silence.
+const DtxSIDSize = 1
+
+const (
+	// dtxHangoverFrames keeps transmitting briefly after speech ends so
+	// trailing syllables are not clipped.
+	dtxHangoverFrames = 8
+	// dtxSIDInterval is the number of silent frames between SID updates.
+	dtxSIDInterval = 8
+)
+
+// DtxEncoder implements discontinuous transmission: frames are only sent
+// while speech is present, and silence is replaced by occasional SID frames
+// describing the background noise level.
+type DtxEncoder struct {
+	vad          *Vad
+	sampleRate   int
+	frameSize    int
+	hangover     int
+	silentFrames int
+}
+
+// NewDtxEncoder creates a new DTX encoder.
+//
+// Parameters:
+//   - sampleRate: Audio sample rate in Hz
+//   - frameSize: Number of samples per frame, 10, 20 or 30ms long
+//   - mode: VAD sensitivity used to classify frames
+func NewDtxEncoder(sampleRate, frameSize int, mode VadMode) (*DtxEncoder, error) {
+	if err := validate("DTX encoder",
+		checkSampleRate(sampleRate),
+		checkVadFrameSize(sampleRate, frameSize),
+	); err != nil {
+		return nil, err
+	}
+	vad, err := NewVad(sampleRate, mode)
+	if err != nil {
+		return nil, err
+	}
+	return &DtxEncoder{vad: vad, sampleRate: sampleRate, frameSize: frameSize}, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (d *DtxEncoder) SampleRate() int {
+	return d.sampleRate
+}
+
+// FrameSize returns the number of samples per frame.
+func (d *DtxEncoder) FrameSize() int {
+	return d.frameSize
+}
+
+// Process classifies a frame and decides whether it should be transmitted.
+//
+// When send is true, payload is the frame itself. When send is false, payload
+// is either nil (transmit nothing) or a DtxSIDSize-long SID frame carrying the
+// noise level, which should be forwarded to the DtxDecoder.
+func (d *DtxEncoder) Process(frame []int16) (send bool, payload []int16) {
+	if d.vad == nil || len(frame) == 0 {
+		return false, nil
+	}
+	if d.vad.IsSpeech(frame) {
+		d.hangover = dtxHangoverFrames
+		d.silentFrames = 0
+		return true, frame
+	}
+	if d.hangover > 0 {
+		d.hangover--
+		return true, frame
+	}
+	// The first silent frame always carries a SID so the receiver can
+	// start generating noise at the right level.
+	sid := d.silentFrames%dtxSIDInterval == 0
+	d.silentFrames++
+	if !sid {
+		return false, nil
+	}
+	return false, []int16{int16(math.Round(float64(rmsDbfs(frame))))}
+}
+
+// Close releases the encoder resources.
+func (d *DtxEncoder) Close() error {
+	if d.vad != nil {
+		d.vad.Close()
+		d.vad = nil
+	}
+	return nil
+}
+
+// DtxDecoder reconstructs a continuous stream from DtxEncoder output,
+// regenerating comfort noise for frames that were not transmitted.
+type DtxDecoder struct {
+	cng        *ComfortNoiseGenerator
+	sampleRate int
+	frameSize  int
+}
+
+// NewDtxDecoder creates a new DTX decoder.
+//
+// Parameters:
+//   - sampleRate: Audio sample rate in Hz
+//   - frameSize: Number of samples per frame
+func NewDtxDecoder(sampleRate, frameSize int) (*DtxDecoder, error) {
+	cng, err := NewComfortNoiseGenerator(sampleRate, -60)
+	if err != nil {
+		return nil, err
+	}
+	return &DtxDecoder{cng: cng, sampleRate: sampleRate, frameSize: frameSize}, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (d *DtxDecoder) SampleRate() int {
+	return d.sampleRate
+}
+
+// FrameSize returns the number of samples per frame.
+func (d *DtxDecoder) FrameSize() int {
+	return d.frameSize
+}
+
+// Process returns one frame of audio for a received payload.
+//
+// A nil payload (frame not sent) or a SID payload produces comfort noise;
+// any other payload is returned unchanged as speech.
+func (d *DtxDecoder) Process(payload []int16) []int16 {
+	if d.cng == nil {
+		return nil
+	}
+	switch len(payload) {
+	case 0:
+		return d.cng.Generate(d.frameSize)
+	case DtxSIDSize:
+		d.cng.SetLevel(float32(payload[0]))
+		return d.cng.Generate(d.frameSize)
+	default:
+		return payload
+	}
+}
+
+// Close releases the decoder resources.
+func (d *DtxDecoder) Close() error {
+	if d.cng != nil {
+		d.cng.Close()
+		d.cng = nil
+	}
+	return nil
+}
+
+// rmsDbfs returns the RMS level of samples in dBFS, floored at -100.
+func rmsDbfs(samples []int16) float32 {
+	if len(samples) == 0 {
+		return -100
+	}
+	var sum float64
+	for _, s := range samples {
+		v := float64(s)
+		sum += v * v
+	}
+	rms := math.Sqrt(sum/float64(len(samples))) / 32768
+	if rms <= 0 {
+		return -100
+	}
+	db := 20 * math.Log10(rms)
+	if db < -100 {
+		return -100
+	}
+	return float32(db)
+}
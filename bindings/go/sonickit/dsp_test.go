@@ -1,6 +1,8 @@
 package sonickit
 
 import (
+	"math"
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -21,6 +23,15 @@ func TestVersionInfo(t *testing.T) {
 	t.Logf("SonicKit version: %s", info.String())
 }
 
+func TestBuildFeatures(t *testing.T) {
+	features := BuildFeatures()
+	assert.NotEmpty(t, features)
+	assert.Contains(t, features, "speexdsp")
+	assert.True(t, HasFeature("speexdsp"))
+	assert.False(t, HasFeature("no-such-feature"))
+	t.Logf("SonicKit features: %v", features)
+}
+
 func TestDenoiser(t *testing.T) {
 	denoiser, err := NewDenoiser(16000, 160, DenoiserSpeexDSP)
 	require.NoError(t, err)
@@ -39,6 +50,241 @@ func TestDenoiser(t *testing.T) {
 	denoiser.SetLevel(50)
 }
 
+func TestDenoiserPartialFrames(t *testing.T) {
+	denoiser, err := NewDenoiser(16000, 160, DenoiserSpeexDSP)
+	require.NoError(t, err)
+	defer denoiser.Close()
+
+	// 2.5 frames in uneven chunks
+	total := 0
+	for _, n := range []int{100, 150, 150} {
+		total += len(denoiser.Process(make([]int16, n)))
+	}
+	assert.Equal(t, 320, total)
+	assert.Len(t, denoiser.Flush(), 80)
+	assert.Nil(t, denoiser.Flush())
+
+	// The worst-case hold-back counts towards a pipeline's latency
+	assert.Equal(t, 159, NewPipeline(denoiser).Latency())
+
+	// Frame-aligned input passes straight through
+	assert.Len(t, denoiser.Process(make([]int16, 480)), 480)
+
+	// ProcessWithNoise buffers like Process
+	clean, _ := denoiser.ProcessWithNoise(make([]int16, 100))
+	assert.Nil(t, clean)
+	clean, _ = denoiser.ProcessWithNoise(make([]int16, 100))
+	assert.Len(t, clean, 160)
+
+	// The unbuffered paths refuse to overtake the 40 samples held back,
+	// and take only whole frames
+	dst := make([]int16, 320)
+	assert.Zero(t, denoiser.ProcessInto(dst, make([]int16, 320)))
+	assert.Nil(t, denoiser.Process32(make([]int32, 320)))
+	assert.Len(t, denoiser.Flush(), 40)
+	assert.Equal(t, 320, denoiser.ProcessInto(dst, make([]int16, 320)))
+	assert.Zero(t, denoiser.ProcessInto(dst, make([]int16, 100)))
+	assert.Len(t, denoiser.Process32(make([]int32, 320)), 320)
+	assert.Nil(t, denoiser.Process32(make([]int32, 100)))
+}
+
+func TestDenoiserSetMix(t *testing.T) {
+	denoiser, err := NewDenoiser(16000, 160, DenoiserSpeexDSP)
+	require.NoError(t, err)
+	defer denoiser.Close()
+	denoiser.SetLevel(100)
+
+	input := WhiteNoise(1600, 0.3, 1)
+	full := denoiser.Process(input)
+	assert.NotEqual(t, input, full)
+
+	denoiser.SetMix(0)
+	assert.Equal(t, input, denoiser.Process(input))
+
+	// Halfway sits between the dry and fully denoised levels
+	denoiser.SetMix(0.5)
+	half := rmsDbfs(denoiser.Process(input))
+	assert.Less(t, half, rmsDbfs(input))
+	assert.Greater(t, half, rmsDbfs(full))
+
+	// In place, the dry signal is still the input
+	denoiser.SetMix(0)
+	buf := append([]int16(nil), input...)
+	require.Equal(t, len(buf), denoiser.ProcessInto(buf, buf))
+	assert.Equal(t, input, buf)
+
+	// Loud dry and denoised samples of opposite sign blend without wrapping
+	denoiser.SetMix(0.5)
+	clean := []int16{20000, -20000, 32767}
+	denoiser.blend(clean, []int16{-20000, 20000, -32767})
+	assert.Equal(t, []int16{0, 0, 0}, clean)
+}
+
+func TestCreateErrorNotStale(t *testing.T) {
+	// A failed create leaves the process-wide native error set unless
+	// createError reads it, as the RNNoise support check does not
+	_, err := NewDenoiser(16000, 160, DenoiserRNNoise)
+	require.Error(t, err)
+
+	// Constructors clear it before their own create call, so one that fails
+	// without recording a cause does not report the stale one
+	clearError()
+	assert.EqualError(t, createError("reverb"), "failed to create reverb")
+}
+
+func TestDenoiserInvalidSampleRate(t *testing.T) {
+	d, err := NewDenoiser(12345, 160, DenoiserSpeexDSP)
+	require.Error(t, err)
+	assert.Nil(t, d)
+	assert.ErrorIs(t, err, ErrInvalidSampleRate)
+	assert.Contains(t, err.Error(), "12345")
+}
+
+func TestConstructorValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		create func() error
+		want   error
+	}{
+		{"denoiser sample rate", func() error { _, err := NewDenoiser(0, 160, DenoiserSpeexDSP); return err }, ErrInvalidSampleRate},
+		{"denoiser frame size", func() error { _, err := NewDenoiser(16000, -1, DenoiserSpeexDSP); return err }, ErrInvalidFrameSize},
+		{"resampler channels", func() error { _, err := NewResampler(0, 16000, 48000, 5); return err }, ErrInvalidChannelCount},
+		{"resampler rate", func() error { _, err := NewResampler(1, -16000, 48000, 5); return err }, ErrInvalidSampleRate},
+		{"resampler quality low", func() error { _, err := NewResampler(1, 16000, 48000, -1); return err }, ErrInvalidRange},
+		{"resampler quality high", func() error { _, err := NewResampler(1, 16000, 48000, 11); return err }, ErrInvalidRange},
+		{"mixer channels", func() error { _, err := NewAudioMixer(0, 160); return err }, ErrInvalidChannelCount},
+		{"mixer frame size", func() error { _, err := NewAudioMixer(2, 0); return err }, ErrInvalidFrameSize},
+		{"reverb room size", func() error { _, err := NewReverb(48000, 1.5, 0.3); return err }, ErrInvalidRange},
+		{"chorus depth", func() error { _, err := NewChorus(48000, -0.1, 1.5); return err }, ErrInvalidRange},
+		{"delay feedback", func() error { _, err := NewDelay(48000, 250, 2); return err }, ErrInvalidRange},
+		{"delay endless feedback", func() error { _, err := NewDelay(48000, 250, 1); return err }, ErrInvalidRange},
+		{"envelope attack", func() error { _, err := NewEnvelopeFollower(48000, -1, 100); return err }, ErrInvalidRange},
+		{"saturator sample rate", func() error { _, err := NewSaturator(0, 1, SaturationSoft); return err }, ErrInvalidSampleRate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.create(), tt.want)
+		})
+	}
+}
+
+func TestProcessorAccessors(t *testing.T) {
+	agc, err := NewAgc(16000, 160, AgcAdaptive, -3)
+	require.NoError(t, err)
+	defer agc.Close()
+	assert.Equal(t, 16000, agc.SampleRate())
+	assert.Equal(t, 160, agc.FrameSize())
+
+	resampler, err := NewResampler(1, 16000, 48000, 5)
+	require.NoError(t, err)
+	defer resampler.Close()
+	assert.Equal(t, 16000, resampler.SampleRate())
+	assert.Equal(t, 16000, resampler.InRate())
+	assert.Equal(t, 48000, resampler.OutRate())
+
+	eq, err := NewEqualizer(48000, 5)
+	require.NoError(t, err)
+	defer eq.Close()
+	assert.Equal(t, 48000, eq.SampleRate())
+
+	env, err := NewEnvelopeFollower(44100, 5, 50)
+	require.NoError(t, err)
+	assert.Equal(t, 44100, env.SampleRate())
+}
+
+func TestDenoiserSpectralSub(t *testing.T) {
+	denoiser, err := NewDenoiser(16000, 160, DenoiserSpectralSub)
+	require.NoError(t, err)
+	require.NotNil(t, denoiser)
+	defer denoiser.Close()
+
+	denoiser.SetOverSubtraction(2.0)
+
+	input := make([]int16, 160)
+	for i := range input {
+		input[i] = int16(i * 100)
+	}
+	output := denoiser.Process(input)
+	assert.Len(t, output, len(input))
+}
+
+func TestDenoiserSetLevelRamp(t *testing.T) {
+	denoiser, err := NewDenoiser(16000, 160, DenoiserSpeexDSP)
+	require.NoError(t, err)
+	defer denoiser.Close()
+
+	denoiser.SetLevel(20)
+	assert.Equal(t, 20, denoiser.Level())
+
+	// Ramp over 50ms, then process 100ms so it completes
+	denoiser.SetLevelRamp(80, 50)
+	input := make([]int16, 160)
+	for i := 0; i < 10; i++ {
+		denoiser.Process(input)
+	}
+	assert.Equal(t, 80, denoiser.Level())
+}
+
+func TestDenoiserProcess32(t *testing.T) {
+	denoiser, err := NewDenoiser(16000, 160, DenoiserSpeexDSP)
+	require.NoError(t, err)
+	defer denoiser.Close()
+
+	// A full-scale 24-bit ramp, one frame at a time
+	input := make([]int32, 1600)
+	for i := range input {
+		input[i] = int32(Int24Min + int64(i)*(Int24Max-Int24Min)/int64(len(input)-1))
+	}
+	var output []int32
+	for i := 0; i < len(input); i += 160 {
+		frame := denoiser.Process32(input[i : i+160])
+		require.Len(t, frame, 160)
+		output = append(output, frame...)
+	}
+
+	var peak int32
+	for _, s := range output {
+		require.GreaterOrEqual(t, s, int32(Int24Min))
+		require.LessOrEqual(t, s, int32(Int24Max))
+		if s < 0 {
+			s = -s
+		}
+		if s > peak {
+			peak = s
+		}
+	}
+	// Still well beyond the 16-bit range, so nothing was downconverted
+	assert.Greater(t, peak, int32(math.MaxInt16))
+
+	assert.Nil(t, denoiser.Process32(nil))
+}
+
+func TestDenoiserProcessWithNoise(t *testing.T) {
+	measure := func(amplitude float64) float32 {
+		denoiser, err := NewDenoiser(16000, 160, DenoiserSpeexDSP)
+		require.NoError(t, err)
+		defer denoiser.Close()
+
+		rng := rand.New(rand.NewSource(1))
+		input := make([]int16, 160)
+		var noiseDb float32
+		for frame := 0; frame < 50; frame++ {
+			for i := range input {
+				input[i] = int16(amplitude * rng.NormFloat64())
+			}
+			var clean []int16
+			clean, noiseDb = denoiser.ProcessWithNoise(input)
+			require.Len(t, clean, len(input))
+		}
+		return noiseDb
+	}
+
+	noisy := measure(3000)
+	quiet := measure(5)
+	assert.Greater(t, noisy, quiet)
+	assert.LessOrEqual(t, noisy, float32(0))
+}
+
 func TestEchoCanceller(t *testing.T) {
 	aec, err := NewEchoCanceller(16000, 160, 2000)
 	require.NoError(t, err)
@@ -53,6 +299,37 @@ func TestEchoCanceller(t *testing.T) {
 	}
 	output := aec.Process(captured, playback)
 	assert.Len(t, output, len(captured))
+	assert.Equal(t, 159, aec.Latency())
+}
+
+func TestEchoCancellerFilterTaps(t *testing.T) {
+	aec, err := NewEchoCanceller(16000, 160, 1024)
+	require.NoError(t, err)
+	defer aec.Close()
+
+	// The captured signal is an attenuated, delayed copy of the playback
+	rng := rand.New(rand.NewSource(1))
+	playback := make([]int16, 160*200)
+	for i := range playback {
+		playback[i] = int16(4000 * rng.NormFloat64())
+	}
+	captured := make([]int16, len(playback))
+	for i := 40; i < len(captured); i++ {
+		captured[i] = playback[i-40] / 2
+	}
+	for start := 0; start < len(playback); start += 160 {
+		aec.Process(captured[start:start+160], playback[start:start+160])
+	}
+
+	taps := aec.FilterTaps()
+	require.Len(t, taps, 1024)
+	nonZero := 0
+	for _, tap := range taps {
+		if tap != 0 {
+			nonZero++
+		}
+	}
+	assert.Greater(t, nonZero, 0)
 }
 
 func TestAgc(t *testing.T) {
@@ -72,6 +349,31 @@ func TestAgc(t *testing.T) {
 	t.Logf("AGC gain: %.2f dB", gain)
 }
 
+func TestAgcPartialFrames(t *testing.T) {
+	agc, err := NewAgc(16000, 160, AgcAdaptive, -3)
+	require.NoError(t, err)
+	defer agc.Close()
+
+	total := 0
+	for _, n := range []int{100, 150, 150} {
+		total += len(agc.Process(make([]int16, n)))
+	}
+	total += len(agc.Flush())
+	assert.Equal(t, 400, total)
+	assert.Equal(t, 159, NewPipeline(agc).Latency())
+
+	// The unbuffered paths take only whole frames, and never while Process
+	// holds a partial frame back
+	dst := make([]int16, 320)
+	assert.Zero(t, agc.ProcessInto(dst, make([]int16, 100)))
+	agc.Process(make([]int16, 100))
+	assert.Zero(t, agc.ProcessInto(dst, make([]int16, 320)))
+	assert.Nil(t, agc.Process32(make([]int32, 320)))
+	agc.Flush()
+	assert.Equal(t, 320, agc.ProcessInto(dst, make([]int16, 320)))
+	assert.Len(t, agc.Process32(make([]int32, 320)), 320)
+}
+
 func TestVad(t *testing.T) {
 	vad, err := NewVad(16000, VadLowBitrate)
 	require.NoError(t, err)
@@ -88,6 +390,44 @@ func TestVad(t *testing.T) {
 	assert.LessOrEqual(t, prob, float32(1))
 }
 
+func TestVadAnalyzeBlock(t *testing.T) {
+	vad, err := NewVad(16000, VadQuality)
+	require.NoError(t, err)
+	defer vad.Close()
+
+	// 500ms block: silence, a 200ms voiced burst, silence
+	const sub = 160
+	input := make([]int16, 50*sub)
+	for i := 15 * sub; i < 35*sub; i++ {
+		sec := float64(i) / 16000
+		v := 0.0
+		for h := 1; h <= 8; h++ {
+			v += math.Sin(2*math.Pi*150*float64(h)*sec) / float64(h)
+		}
+		input[i] = int16(6000 * v)
+	}
+
+	decisions := vad.AnalyzeBlock(input, sub)
+	require.Len(t, decisions, 50)
+	assert.False(t, decisions[2])
+	assert.False(t, decisions[47])
+	speech := 0
+	for _, d := range decisions[20:30] {
+		if d {
+			speech++
+		}
+	}
+	assert.GreaterOrEqual(t, speech, 8)
+
+	envelope := vad.EnergyEnvelope(input, sub)
+	require.Len(t, envelope, 50)
+	assert.Equal(t, float32(-100), envelope[0])
+	assert.Greater(t, envelope[25], float32(-20))
+
+	assert.Nil(t, vad.AnalyzeBlock(input[:sub+1], sub))
+	assert.Nil(t, vad.EnergyEnvelope(input[:sub+1], sub))
+}
+
 func TestResampler(t *testing.T) {
 	resampler, err := NewResampler(1, 16000, 48000, 5)
 	require.NoError(t, err)
@@ -103,6 +443,105 @@ func TestResampler(t *testing.T) {
 	assert.Greater(t, len(output), 0)
 }
 
+func TestResamplerProcessFixed(t *testing.T) {
+	resampler, err := NewResampler(1, 44100, 48000, 5)
+	require.NoError(t, err)
+	defer resampler.Close()
+
+	input := make([]int16, 441)
+	for i := range input {
+		input[i] = int16(i * 50)
+	}
+
+	// Allow the filter delay to prime, then expect steady 10ms frames
+	delivered := 0
+	for i := 0; i < 200; i++ {
+		output, err := resampler.ProcessFixed(input, 480)
+		if i < 5 && err == ErrNeedMoreInput {
+			continue
+		}
+		require.NoError(t, err, "frame %d", i)
+		assert.Len(t, output, 480)
+		delivered++
+	}
+	assert.GreaterOrEqual(t, delivered, 195)
+}
+
+func TestResamplerDriftPPM(t *testing.T) {
+	// Ten minutes of 16kHz input in 20ms frames through three resamplers
+	// that differ only in drift correction
+	total := func(ppm float32) int {
+		r, err := NewResampler(1, 16000, 16000, 5)
+		require.NoError(t, err)
+		defer r.Close()
+		r.SetDriftPPM(ppm)
+		assert.Equal(t, ppm, r.DriftPPM())
+
+		frame := make([]int16, 320)
+		n := 0
+		for i := 0; i < 30000; i++ {
+			n += len(r.Process(frame))
+		}
+		return n
+	}
+
+	const input = 30000 * 320 // 9.6M samples
+	neutral, faster, slower := total(0), total(100), total(-100)
+	// 100ppm of 9.6M samples is 960 samples
+	assert.InDelta(t, input, neutral, 64)
+	assert.InDelta(t, neutral+960, faster, 64)
+	assert.InDelta(t, neutral-960, slower, 64)
+}
+
+func TestResamplerSNR(t *testing.T) {
+	low := ResamplerSNR(44100, 48000, 1)
+	high := ResamplerSNR(44100, 48000, 10)
+	assert.Greater(t, low, float32(0))
+	assert.Greater(t, high, low)
+
+	// Rates too low for 10ms blocks still finish
+	assert.NotPanics(t, func() { ResamplerSNR(50, 48000, 5) })
+}
+
+func TestResamplerProcessReuse(t *testing.T) {
+	resampler, err := NewResampler(1, 16000, 48000, 5)
+	require.NoError(t, err)
+	defer resampler.Close()
+
+	input := make([]int16, 160)
+	for i := range input {
+		input[i] = int16(i * 50)
+	}
+
+	// A too-small scratch is replaced, after which the buffer is reused
+	scratch := resampler.ProcessReuse(input, nil)
+	require.NotEmpty(t, scratch)
+	output := resampler.ProcessReuse(input, scratch)
+	assert.Same(t, &scratch[:1][0], &output[:1][0])
+}
+
+func BenchmarkResamplerProcess(b *testing.B) {
+	resampler, _ := NewResampler(1, 16000, 48000, 5)
+	defer resampler.Close()
+	input := make([]int16, 160)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = resampler.Process(input)
+	}
+}
+
+func BenchmarkResamplerProcessReuse(b *testing.B) {
+	resampler, _ := NewResampler(1, 16000, 48000, 5)
+	defer resampler.Close()
+	input := make([]int16, 160)
+	scratch := resampler.ProcessReuse(input, nil)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		scratch = resampler.ProcessReuse(input, scratch)
+	}
+}
+
 func TestDtmfGenerator(t *testing.T) {
 	generator, err := NewDtmfGenerator(8000, 100)
 	require.NoError(t, err)
@@ -149,6 +588,37 @@ func TestEqualizer(t *testing.T) {
 	assert.Len(t, output, len(input))
 }
 
+func TestEqualizerClone(t *testing.T) {
+	eq, err := NewEqualizer(48000, 3)
+	require.NoError(t, err)
+	defer eq.Close()
+	eq.SetBand(0, 100, 6, 0.7)
+	eq.SetBand(2, 8000, -4, 2)
+
+	clone, err := eq.Clone()
+	require.NoError(t, err)
+	defer clone.Close()
+	assert.Equal(t, eq.Params(), clone.Params())
+
+	// Changing either one leaves the other untouched
+	clone.SetBand(0, 200, -12, 1)
+	eq.SetBand(1, 1000, 3, 1)
+	assert.Equal(t, eqBand{100, 6, 0.7}, eq.settings[0])
+	assert.Equal(t, eqBand{200, -12, 1}, clone.settings[0])
+	assert.NotEqual(t, eq.settings[1], clone.settings[1])
+	assert.Equal(t, eq.settings[2], clone.settings[2])
+
+	input := make([]int16, 480)
+	for i := range input {
+		input[i] = int16(8000 * math.Sin(2*math.Pi*100*float64(i)/48000))
+	}
+	assert.Len(t, clone.Process(input), len(input))
+
+	eq.Close()
+	_, err = eq.Clone()
+	assert.Error(t, err)
+}
+
 func TestCompressor(t *testing.T) {
 	comp, err := NewCompressor(48000, -20, 4.0, 10, 100)
 	require.NoError(t, err)
@@ -166,6 +636,140 @@ func TestCompressor(t *testing.T) {
 	t.Logf("Gain reduction: %.2f dB", gr)
 }
 
+func TestEqualizerProcessInPlace(t *testing.T) {
+	eq, err := NewEqualizer(48000, 5)
+	require.NoError(t, err)
+	defer eq.Close()
+	eq.SetBand(0, 100, 3.0, 1.0)
+
+	ref, err := NewEqualizer(48000, 5)
+	require.NoError(t, err)
+	defer ref.Close()
+	ref.SetBand(0, 100, 3.0, 1.0)
+
+	buf := make([]int16, 480)
+	for i := range buf {
+		buf[i] = int16(i * 10)
+	}
+	expected := ref.Process(buf)
+	eq.ProcessInPlace(buf)
+	assert.Equal(t, expected, buf)
+}
+
+func BenchmarkEqualizerProcess(b *testing.B) {
+	eq, _ := NewEqualizer(48000, 5)
+	defer eq.Close()
+	buf := make([]int16, 960)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = eq.Process(buf)
+	}
+}
+
+func BenchmarkEqualizerProcessInPlace(b *testing.B) {
+	eq, _ := NewEqualizer(48000, 5)
+	defer eq.Close()
+	buf := make([]int16, 960)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		eq.ProcessInPlace(buf)
+	}
+}
+
+func BenchmarkCompressorProcess(b *testing.B) {
+	comp, _ := NewCompressor(48000, -20, 4.0, 10, 100)
+	defer comp.Close()
+	buf := make([]int16, 960)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = comp.Process(buf)
+	}
+}
+
+func BenchmarkCompressorProcessInPlace(b *testing.B) {
+	comp, _ := NewCompressor(48000, -20, 4.0, 10, 100)
+	defer comp.Close()
+	buf := make([]int16, 960)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		comp.ProcessInPlace(buf)
+	}
+}
+
+func TestMultibandCompressor(t *testing.T) {
+	mb, err := NewMultibandCompressor(48000, []float32{200, 2000})
+	require.NoError(t, err)
+	require.NotNil(t, mb)
+	defer mb.Close()
+	assert.Equal(t, 3, mb.Bands())
+
+	for band := 0; band < mb.Bands(); band++ {
+		mb.SetBand(band, -30, 4.0, 5, 100)
+	}
+
+	// Loud 5kHz tone sits entirely in the top band
+	input := make([]int16, 480)
+	for n := 0; n < 50; n++ {
+		for i := range input {
+			input[i] = int16(25000 * math.Sin(2*math.Pi*5000*float64(n*480+i)/48000))
+		}
+		assert.Len(t, mb.Process(input), len(input))
+	}
+
+	assert.InDelta(t, 0, mb.GainReduction(0), 0.5)
+	assert.InDelta(t, 0, mb.GainReduction(1), 0.5)
+	assert.Greater(t, abs32(mb.GainReduction(2)), float32(3))
+}
+
+func abs32(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func TestCrossoverReconstruction(t *testing.T) {
+	const sampleRate = 48000
+	xo, err := NewCrossover(sampleRate, []float32{200, 2000})
+	require.NoError(t, err)
+	require.NotNil(t, xo)
+	defer xo.Close()
+	assert.Equal(t, 3, xo.Bands())
+
+	// One tone per band plus one at each crossover point
+	for _, freq := range []float64{60, 200, 700, 2000, 8000} {
+		input := make([]int16, sampleRate/2)
+		for i := range input {
+			input[i] = int16(8000 * math.Sin(2*math.Pi*freq*float64(i)/sampleRate))
+		}
+		bands := xo.Split(input)
+		require.Len(t, bands, 3)
+
+		sum := make([]int16, len(input))
+		for _, band := range bands {
+			require.Len(t, band, len(input))
+			for i, s := range band {
+				sum[i] += s
+			}
+		}
+		// The bands sum to an all-pass response, so compare levels after
+		// the filters settle rather than individual samples
+		settled := len(input) / 2
+		assert.InDelta(t, rmsDbfs(input[settled:]), rmsDbfs(sum[settled:]), 0.5, "%.0f Hz", freq)
+	}
+
+	_, err = NewCrossover(sampleRate, []float32{2000, 200})
+	assert.ErrorIs(t, err, ErrInvalidRange)
+	_, err = NewCrossover(sampleRate, []float32{30000})
+	assert.ErrorIs(t, err, ErrInvalidRange)
+
+	// Empty bands: at 0 Hz, between equal frequencies or at Nyquist
+	for _, freqs := range [][]float32{{0}, {200, 200}, {sampleRate / 2}} {
+		_, err = NewCrossover(sampleRate, freqs)
+		assert.ErrorIs(t, err, ErrInvalidRange, "%v", freqs)
+	}
+}
+
 func TestComfortNoiseGenerator(t *testing.T) {
 	cng, err := NewComfortNoiseGenerator(16000, -40)
 	require.NoError(t, err)
@@ -178,3 +782,277 @@ func TestComfortNoiseGenerator(t *testing.T) {
 	// Set level
 	cng.SetLevel(-50)
 }
+
+func TestSetDitherSeed(t *testing.T) {
+	run := func() []int16 {
+		SetDitherSeed(42)
+		cng, err := NewComfortNoiseGenerator(16000, -50)
+		require.NoError(t, err)
+		defer cng.Close()
+		return cng.Generate(1600)
+	}
+	first := run()
+	assert.Equal(t, first, run())
+
+	SetDitherSeed(43)
+	cng, err := NewComfortNoiseGenerator(16000, -50)
+	require.NoError(t, err)
+	defer cng.Close()
+	assert.NotEqual(t, first, cng.Generate(1600))
+}
+
+func TestFeedbackSuppressor(t *testing.T) {
+	fs, err := NewFeedbackSuppressor(48000, 4)
+	require.NoError(t, err)
+	require.NotNil(t, fs)
+	defer fs.Close()
+
+	assert.Empty(t, fs.ActiveNotches())
+
+	// Feed one second of a strong sustained 2kHz tone
+	frame := make([]int16, 480)
+	for n := 0; n < 100; n++ {
+		for i := range frame {
+			phase := 2 * math.Pi * 2000 * float64(n*len(frame)+i) / 48000
+			frame[i] = int16(20000 * math.Sin(phase))
+		}
+		output := fs.Process(frame)
+		assert.Len(t, output, len(frame))
+	}
+
+	notches := fs.ActiveNotches()
+	require.NotEmpty(t, notches)
+	found := false
+	for _, hz := range notches {
+		if math.Abs(float64(hz)-2000) < 50 {
+			found = true
+		}
+	}
+	assert.True(t, found, "expected a notch near 2kHz, got %v", notches)
+}
+
+func TestTempoDetector(t *testing.T) {
+	detector, err := NewTempoDetector(44100)
+	require.NoError(t, err)
+	require.NotNil(t, detector)
+	defer detector.Close()
+
+	// Ten seconds of a 120 BPM click track (one 5ms click every 0.5s)
+	track := make([]int16, 44100*10)
+	for beat := 0; beat < len(track); beat += 22050 {
+		for i := 0; i < 220 && beat+i < len(track); i++ {
+			track[beat+i] = int16(20000 * math.Sin(2*math.Pi*1000*float64(i)/44100))
+		}
+	}
+
+	var bpm, confidence float32
+	for start := 0; start+1024 <= len(track); start += 1024 {
+		bpm, confidence = detector.Process(track[start : start+1024])
+	}
+	assert.InDelta(t, 120, bpm, 2)
+	assert.Greater(t, confidence, float32(0))
+
+	detector.Reset()
+}
+
+func TestOnsetDetector(t *testing.T) {
+	detector, err := NewOnsetDetector(48000)
+	require.NoError(t, err)
+	require.NotNil(t, detector)
+	defer detector.Close()
+	detector.SetSensitivity(0.5)
+
+	// One second of silence with four sharp, decaying bursts
+	positions := []int{6000, 18000, 30000, 42000}
+	audio := make([]int16, 48000)
+	for _, pos := range positions {
+		for i := 0; i < 4800; i++ {
+			v := 20000 * math.Exp(-float64(i)/500)
+			if i%2 == 1 {
+				v = -v
+			}
+			audio[pos+i] = int16(v)
+		}
+	}
+
+	var onsets []int
+	for start := 0; start < len(audio); start += 512 {
+		end := start + 512
+		if end > len(audio) {
+			end = len(audio)
+		}
+		for _, off := range detector.Process(audio[start:end]) {
+			onsets = append(onsets, start+off)
+		}
+	}
+
+	// Each onset is reported once, within 10ms of the burst
+	require.Len(t, onsets, len(positions))
+	for i, pos := range positions {
+		assert.InDelta(t, pos, onsets[i], 480)
+	}
+
+	assert.Nil(t, detector.Process(nil))
+}
+
+func TestPitchDetector(t *testing.T) {
+	detector, err := NewPitchDetector(48000, 60, 1000)
+	require.NoError(t, err)
+	require.NotNil(t, detector)
+	defer detector.Close()
+
+	// 50ms of 220Hz
+	input := make([]int16, 2400)
+	for i := range input {
+		input[i] = int16(10000 * math.Sin(2*math.Pi*220*float64(i)/48000))
+	}
+	hz, confidence := detector.Process(input)
+	require.Greater(t, hz, float32(0))
+	cents := 1200 * math.Log2(float64(hz)/220)
+	assert.InDelta(t, 0, cents, 5, "detected %.2f Hz", hz)
+	assert.Greater(t, confidence, float32(0.5))
+
+	hz, confidence = detector.Process(make([]int16, 2400))
+	assert.Zero(t, hz)
+	assert.Zero(t, confidence)
+
+	_, err = NewPitchDetector(48000, 500, 100)
+	assert.ErrorIs(t, err, ErrInvalidRange)
+}
+
+func TestFingerprinter(t *testing.T) {
+	noise := func(seed int64) []int16 {
+		rng := rand.New(rand.NewSource(seed))
+		samples := make([]int16, 32000)
+		for i := range samples {
+			samples[i] = int16(rng.Intn(20000) - 10000)
+		}
+		return samples
+	}
+	fingerprint := func(samples []int16) []uint32 {
+		f, err := NewFingerprinter(16000)
+		require.NoError(t, err)
+		defer f.Close()
+		for start := 0; start < len(samples); start += 320 {
+			f.Process(samples[start : start+320])
+		}
+		return f.Fingerprint()
+	}
+
+	a := fingerprint(noise(1))
+	require.NotEmpty(t, a)
+	assert.InDelta(t, 1, MatchFingerprints(a, fingerprint(noise(1))), 0.01)
+	assert.Less(t, MatchFingerprints(a, fingerprint(noise(2))), float32(0.75))
+	assert.Zero(t, MatchFingerprints(a, nil))
+}
+
+func TestSpectralGate(t *testing.T) {
+	gate, err := NewSpectralGate(16000, 512)
+	require.NoError(t, err)
+	defer gate.Close()
+
+	rng := rand.New(rand.NewSource(1))
+	noise := func(n int) []int16 {
+		samples := make([]int16, n)
+		for i := range samples {
+			samples[i] = int16(rng.Intn(1000) - 500)
+		}
+		return samples
+	}
+	gate.Learn(noise(16000))
+
+	// Half a second of noise, then a tone over the same noise
+	input := noise(16000)
+	for i := 8000; i < len(input); i++ {
+		input[i] += int16(8000 * math.Sin(2*math.Pi*440*float64(i)/16000))
+	}
+	output := gate.Process(input)
+	require.Len(t, output, len(input))
+
+	// The noise-only part drops by at least 6dB, allowing for latency
+	floor := gate.Latency()
+	assert.Less(t, rmsDbfs(output[floor:8000]), rmsDbfs(input[floor:8000])-6)
+
+	_, err = NewSpectralGate(16000, 1000)
+	assert.ErrorIs(t, err, ErrInvalidRange)
+}
+
+func TestSpectrumAnalyzer(t *testing.T) {
+	// 16kHz over 1024 bins puts 1kHz exactly on bin 64
+	centered := Sine(16000, 1000, 0.5, 1024)
+	// Halfway between bins 64 and 65, the worst case for leakage
+	between := Sine(16000, 1007.8125, 0.5, 1024)
+
+	for _, tc := range []struct {
+		window  WindowType
+		leakage float32 // level ten bins from the tone, relative to its peak
+	}{
+		{WindowRectangular, -20},
+		{WindowHann, -60},
+		{WindowHamming, -40},
+		{WindowBlackman, -70},
+	} {
+		analyzer, err := NewSpectrumAnalyzer(16000, 1024, tc.window)
+		require.NoError(t, err)
+		assert.Equal(t, float32(1000), analyzer.BinToHz(64))
+
+		spectrum := analyzer.Process(centered)
+		require.Len(t, spectrum, 513)
+		assert.InDelta(t, -6.02, spectrum[64], 0.1, "window %d", tc.window)
+
+		spectrum = analyzer.Process(between)
+		peak := 0
+		for bin, db := range spectrum {
+			if db > spectrum[peak] {
+				peak = bin
+			}
+		}
+		assert.Contains(t, []int{64, 65}, peak, "window %d", tc.window)
+		assert.Less(t, spectrum[75]-spectrum[peak], tc.leakage, "window %d", tc.window)
+		analyzer.Close()
+	}
+
+	_, err := NewSpectrumAnalyzer(16000, 1000, WindowHann)
+	assert.ErrorIs(t, err, ErrInvalidRange)
+}
+
+func TestClickRemover(t *testing.T) {
+	remover, err := NewClickRemover(16000)
+	require.NoError(t, err)
+	require.NotNil(t, remover)
+	defer remover.Close()
+
+	// Quiet tone with a single-sample click
+	input := make([]int16, 320)
+	for i := range input {
+		input[i] = int16(1000 * math.Sin(2*math.Pi*200*float64(i)/16000))
+	}
+	input[160] = 30000
+	output := remover.Process(input)
+	require.Len(t, output, len(input))
+	assert.Less(t, output[160], int16(10000))
+}
+
+func TestDtxSilenceSuppression(t *testing.T) {
+	enc, err := NewDtxEncoder(16000, 320, VadQuality)
+	require.NoError(t, err)
+	require.NotNil(t, enc)
+	defer enc.Close()
+
+	dec, err := NewDtxDecoder(16000, 320)
+	require.NoError(t, err)
+	require.NotNil(t, dec)
+	defer dec.Close()
+
+	silence := make([]int16, 320)
+	sent := 0
+	for i := 0; i < 100; i++ {
+		send, payload := enc.Process(silence)
+		if send {
+			sent++
+		}
+		// Every frame, sent or not, decodes to a full frame
+		assert.Len(t, dec.Process(payload), 320)
+	}
+	assert.Less(t, sent, 10)
+}
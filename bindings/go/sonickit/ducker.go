@@ -0,0 +1,102 @@
+package sonickit
+
+import "math"
+
+// Side-chain detector settings for Ducker. The detector reacts quickly to
+// speech onsets and bridges the short gaps between words.
+const (
+	duckDetectAttackMs  = 5
+	duckDetectReleaseMs = 50
+	duckDefaultThreshDb = -40
+)
+
+// Ducker lowers a music (or other background) signal while a side-chain
+// signal, typically a voiceover, is active.
+type Ducker struct {
+	sampleRate    int
+	detector      *EnvelopeFollower
+	attenuationDb float64
+	thresholdDb   float32
+	attackCoef    float64
+	releaseCoef   float64
+	gainDb        float64
+}
+
+// NewDucker creates a new ducker.
+//
+// Parameters:
+//   - sampleRate: Audio sample rate in Hz
+//   - attenuationDb: Reduction applied to the music while voice is present,
+//     in dB (e.g. 12)
+//   - attackMs: Time to duck once voice starts, in milliseconds
+//   - releaseMs: Time to recover once voice stops, in milliseconds
+func NewDucker(sampleRate int, attenuationDb, attackMs, releaseMs float32) (*Ducker, error) {
+	if err := validate("ducker",
+		checkPositiveRate(sampleRate),
+		checkRange("attack", float64(attackMs), 0, math.MaxFloat32),
+		checkRange("release", float64(releaseMs), 0, math.MaxFloat32),
+	); err != nil {
+		return nil, err
+	}
+	detector, err := NewEnvelopeFollower(sampleRate, duckDetectAttackMs, duckDetectReleaseMs)
+	if err != nil {
+		return nil, err
+	}
+	detector.SetScale(EnvelopeDb)
+	return &Ducker{
+		sampleRate:    sampleRate,
+		detector:      detector,
+		attenuationDb: -math.Abs(float64(attenuationDb)),
+		thresholdDb:   duckDefaultThreshDb,
+		attackCoef:    timeConstantCoef(sampleRate, attackMs),
+		releaseCoef:   timeConstantCoef(sampleRate, releaseMs),
+	}, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (d *Ducker) SampleRate() int {
+	return d.sampleRate
+}
+
+// SetThreshold sets the side-chain level in dBFS above which voice is
+// considered present. The default is -40dBFS.
+func (d *Ducker) SetThreshold(db float32) {
+	d.thresholdDb = db
+}
+
+// GainReduction returns the current reduction applied to the music in dB,
+// as a positive number.
+func (d *Ducker) GainReduction() float32 {
+	return float32(-d.gainDb)
+}
+
+// Process returns music with the ducking gain applied. voiceSidechain is
+// only analyzed, never mixed into the output; it should be time-aligned
+// with music, and any samples missing from its end are treated as silence.
+func (d *Ducker) Process(music, voiceSidechain []int16) []int16 {
+	if len(music) == 0 {
+		return nil
+	}
+	output := make([]int16, len(music))
+	for i, s := range music {
+		var side int16
+		if i < len(voiceSidechain) {
+			side = voiceSidechain[i]
+		}
+		d.detector.next(side)
+
+		target, coef := 0.0, d.releaseCoef
+		if d.detector.Current() > d.thresholdDb {
+			target, coef = d.attenuationDb, d.attackCoef
+		}
+		d.gainDb = coef*d.gainDb + (1-coef)*target
+		output[i] = clampInt16(float64(s) * dbToGain(d.gainDb))
+	}
+	return output
+}
+
+// Reset returns the ducker to its idle, unducked state.
+func (d *Ducker) Reset() {
+	d.detector.Reset()
+	d.gainDb = 0
+}
@@ -0,0 +1,41 @@
+package sonickit
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDucker(t *testing.T) {
+	const sampleRate = 16000
+	ducker, err := NewDucker(sampleRate, 12, 10, 200)
+	require.NoError(t, err)
+
+	music := make([]int16, sampleRate)
+	for i := range music {
+		music[i] = int16(8000 * math.Sin(2*math.Pi*440*float64(i)/sampleRate))
+	}
+	// Voice only in the second half
+	voice := make([]int16, sampleRate)
+	for i := sampleRate / 2; i < len(voice); i++ {
+		voice[i] = int16(16000 * math.Sin(2*math.Pi*300*float64(i)/sampleRate))
+	}
+
+	output := ducker.Process(music, voice)
+	require.Len(t, output, len(music))
+
+	quiet := rmsDbfs(output[sampleRate/4 : sampleRate/2])
+	ducked := rmsDbfs(output[3*sampleRate/4:])
+	assert.InDelta(t, rmsDbfs(music[sampleRate/4:sampleRate/2]), quiet, 0.1)
+	assert.InDelta(t, quiet-12, ducked, 1)
+	assert.InDelta(t, 12, ducker.GainReduction(), 0.5)
+
+	// Once the voice stops the music recovers
+	output = ducker.Process(music, nil)
+	assert.InDelta(t, quiet, rmsDbfs(output[3*sampleRate/4:]), 1)
+
+	_, err = NewDucker(sampleRate, 12, -1, 200)
+	assert.ErrorIs(t, err, ErrInvalidRange)
+}
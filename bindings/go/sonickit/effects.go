@@ -10,17 +10,45 @@ package sonickit
 #include "dsp/voice_flanger.h"
 #include "dsp/voice_time_stretch.h"
 #include "dsp/voice_watermark.h"
+#include "dsp/voice_stereo_width.h"
+#include "dsp/voice_bitcrush.h"
+#include "dsp/voice_ringmod.h"
+#include "dsp/voice_autowah.h"
+#include "dsp/voice_subharmonic.h"
+#include "dsp/voice_warp.h"
+#include "dsp/voice_conv.h"
 */
 import "C"
 import (
+	"bytes"
+	"encoding/binary"
 	"errors"
+	"fmt"
+	"hash/crc32"
+	"math"
 	"runtime"
 	"unsafe"
 )
 
+// Defaults for the Reverb settings not taken by NewReverb.
+const (
+	reverbDefaultEarly    = 0.5
+	reverbDefaultDecay    = 1.5
+	reverbDefaultDamping  = 0.5
+	reverbDefaultPreDelay = 0
+)
+
 // Reverb provides room reverb effect processing.
 type Reverb struct {
-	handle unsafe.Pointer
+	handle     unsafe.Pointer
+	sampleRate int
+	roomSize   float32
+	wetLevel   float32
+	early      float32
+	decay      float32
+	damping    float32
+	preDelayMs float32
+	wetEvents  []paramEvent
 }
 
 // NewReverb creates a new reverb effect processor.
@@ -30,19 +58,42 @@ type Reverb struct {
 //   - roomSize: Room size factor (0.0-1.0)
 //   - wetLevel: Wet/dry mix level (0.0-1.0)
 func NewReverb(sampleRate int, roomSize, wetLevel float32) (*Reverb, error) {
+	if err := validate("reverb",
+		checkSampleRate(sampleRate),
+		checkUnit("room size", roomSize),
+		checkUnit("wet level", wetLevel),
+	); err != nil {
+		return nil, err
+	}
+	clearError()
 	handle := C.voice_reverb_create(C.int(sampleRate), C.float(roomSize), C.float(wetLevel))
 	if handle == nil {
-		return nil, errors.New("failed to create reverb")
+		return nil, createError("reverb")
+	}
+	r := &Reverb{
+		handle:     handle,
+		sampleRate: sampleRate,
+		roomSize:   roomSize,
+		wetLevel:   wetLevel,
+		early:      reverbDefaultEarly,
+		decay:      reverbDefaultDecay,
+		damping:    reverbDefaultDamping,
+		preDelayMs: reverbDefaultPreDelay,
 	}
-	r := &Reverb{handle: handle}
 	runtime.SetFinalizer(r, (*Reverb).Close)
 	return r, nil
 }
 
+// SampleRate returns the sample rate in Hz.
+func (r *Reverb) SampleRate() int {
+	return r.sampleRate
+}
+
 // SetRoomSize sets the room size.
 func (r *Reverb) SetRoomSize(size float32) {
 	if r.handle != nil {
 		C.voice_reverb_set_room_size(r.handle, C.float(size))
+		r.roomSize = size
 	}
 }
 
@@ -50,22 +101,147 @@ func (r *Reverb) SetRoomSize(size float32) {
 func (r *Reverb) SetWetLevel(level float32) {
 	if r.handle != nil {
 		C.voice_reverb_set_wet_level(r.handle, C.float(level))
+		r.wetLevel = level
+	}
+}
+
+// SetEarlyReflectionsLevel sets the level of the early reflections
+// (0.0-1.0), the first distinct echoes off nearby surfaces that convey the
+// size and shape of the room, relative to the diffuse tail. The default is
+// 0.5.
+func (r *Reverb) SetEarlyReflectionsLevel(level float32) {
+	if r.handle != nil {
+		C.voice_reverb_set_early_level(r.handle, C.float(level))
+		r.early = level
+	}
+}
+
+// SetDecayTime sets how long the tail takes to die away by 60dB (RT60), in
+// seconds. Small rooms measure around 0.3-0.8s, halls 1.5-3s. The default
+// is 1.5s.
+func (r *Reverb) SetDecayTime(seconds float32) {
+	if r.handle != nil {
+		C.voice_reverb_set_decay_time(r.handle, C.float(seconds))
+		r.decay = seconds
+	}
+}
+
+// SetDamping sets how much faster high frequencies decay than low ones
+// (0.0-1.0). Higher values sound darker, like a room full of soft
+// furnishings. The default is 0.5.
+func (r *Reverb) SetDamping(damping float32) {
+	if r.handle != nil {
+		C.voice_reverb_set_damping(r.handle, C.float(damping))
+		r.damping = damping
+	}
+}
+
+// SetPreDelay sets the gap in milliseconds between the dry sound and the
+// onset of the reverb. A few tens of milliseconds keeps speech clear in a
+// large space. The default is 0.
+func (r *Reverb) SetPreDelay(ms float32) {
+	if r.handle != nil {
+		C.voice_reverb_set_pre_delay(r.handle, C.float(ms))
+		r.preDelayMs = ms
 	}
 }
 
+// ScheduleWetLevel schedules a wet level change at sampleOffset within the
+// next Process call. The change is interpolated over a few milliseconds to
+// avoid zipper noise. The schedule is cleared after each Process.
+func (r *Reverb) ScheduleWetLevel(sampleOffset int, level float32) {
+	r.wetEvents = scheduleEvent(r.wetEvents, sampleOffset, level)
+}
+
 // Process applies reverb to the audio.
 func (r *Reverb) Process(input []int16) []int16 {
 	if r.handle == nil || len(input) == 0 {
 		return nil
 	}
 	output := make([]int16, len(input))
+	r.ProcessInto(output, input)
+	return output
+}
+
+// ProcessInto applies reverb to src, writing the result to dst. It returns
+// len(src), or 0 if dst is shorter than src or the reverb is closed.
+// Scheduled wet level changes are applied as in Process. ProcessInto does not
+// allocate unless changes have been scheduled with ScheduleWetLevel.
+func (r *Reverb) ProcessInto(dst, src []int16) int {
+	if r.handle == nil || len(src) == 0 || len(dst) < len(src) {
+		return 0
+	}
+	dst = dst[:len(src)]
+	if len(r.wetEvents) == 0 {
+		r.process(src, dst)
+		return len(src)
+	}
+	r.wetLevel = processAutomated(src, dst, r.wetEvents, r.wetLevel,
+		func(v float32) { C.voice_reverb_set_wet_level(r.handle, C.float(v)) },
+		r.process)
+	r.wetEvents = r.wetEvents[:0]
+	return len(src)
+}
+
+func (r *Reverb) process(input, output []int16) {
 	C.voice_reverb_process(r.handle,
 		(*C.short)(unsafe.Pointer(&input[0])),
 		(*C.short)(unsafe.Pointer(&output[0])),
 		C.int(len(input)))
+}
+
+// ProcessWetOnly applies reverb to the audio and returns only the reverb
+// tail, with the dry signal removed, for blending in parallel with the
+// original. The tail is not scaled by the wet level, and changes scheduled
+// with ScheduleWetLevel apply to Process only.
+func (r *Reverb) ProcessWetOnly(input []int16) []int16 {
+	if r.handle == nil || len(input) == 0 {
+		return nil
+	}
+	output := make([]int16, len(input))
+	C.voice_reverb_process_wet(r.handle,
+		(*C.short)(unsafe.Pointer(&input[0])),
+		(*C.short)(unsafe.Pointer(&output[0])),
+		C.int(len(input)))
 	return output
 }
 
+// ProcessFloat applies reverb to -1..1 float audio. Changes scheduled with
+// ScheduleWetLevel apply to Process only.
+func (r *Reverb) ProcessFloat(input []float32) []float32 {
+	if r.handle == nil || len(input) == 0 {
+		return nil
+	}
+	output := make([]float32, len(input))
+	C.voice_reverb_process_float(r.handle,
+		(*C.float)(unsafe.Pointer(&input[0])),
+		(*C.float)(unsafe.Pointer(&output[0])),
+		C.int(len(input)))
+	return output
+}
+
+// Clone returns an independent reverb with the same settings. The reverb
+// tail and any scheduled changes are not copied.
+func (r *Reverb) Clone() (*Reverb, error) {
+	if r.handle == nil {
+		return nil, errors.New("reverb is closed")
+	}
+	clone, err := NewReverb(r.sampleRate, r.roomSize, r.wetLevel)
+	if err != nil {
+		return nil, err
+	}
+	clone.SetEarlyReflectionsLevel(r.early)
+	clone.SetDecayTime(r.decay)
+	clone.SetDamping(r.damping)
+	clone.SetPreDelay(r.preDelayMs)
+	return clone, nil
+}
+
+// Latency returns 0; the reverb processes sample by sample.
+func (r *Reverb) Latency() int {
+	return 0
+}
+
 // Close releases the reverb resources.
 func (r *Reverb) Close() error {
 	if r.handle != nil {
@@ -76,9 +252,114 @@ func (r *Reverb) Close() error {
 	return nil
 }
 
+// ConvolutionReverb applies the reverb of a measured space by convolving
+// the audio with its impulse response. The convolution is partitioned, so
+// impulse responses much longer than the processing block are handled
+// efficiently via overlap-add.
+type ConvolutionReverb struct {
+	handle     unsafe.Pointer
+	sampleRate int
+}
+
+// NewConvolutionReverb creates a new convolution reverb. The impulse
+// response is copied, so the slice may be reused afterwards. Full scale
+// (32767) in the impulse response corresponds to unity gain.
+//
+// Parameters:
+//   - sampleRate: Audio sample rate in Hz
+//   - impulseResponse: Impulse response at sampleRate
+func NewConvolutionReverb(sampleRate int, impulseResponse []int16) (*ConvolutionReverb, error) {
+	if err := validate("convolution reverb", checkSampleRate(sampleRate)); err != nil {
+		return nil, err
+	}
+	if len(impulseResponse) == 0 {
+		return nil, errors.New("impulse response is empty")
+	}
+	clearError()
+	handle := C.voice_conv_create(C.int(sampleRate),
+		(*C.short)(unsafe.Pointer(&impulseResponse[0])),
+		C.int(len(impulseResponse)))
+	if handle == nil {
+		return nil, createError("convolution reverb")
+	}
+	r := &ConvolutionReverb{handle: handle, sampleRate: sampleRate}
+	runtime.SetFinalizer(r, (*ConvolutionReverb).Close)
+	return r, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (r *ConvolutionReverb) SampleRate() int {
+	return r.sampleRate
+}
+
+// SetWetLevel sets the wet/dry mix level (0.0-1.0). The default of 1.0
+// outputs only the convolved signal.
+func (r *ConvolutionReverb) SetWetLevel(level float32) {
+	if r.handle != nil {
+		C.voice_conv_set_wet_level(r.handle, C.float(level))
+	}
+}
+
+// Process convolves the audio with the impulse response.
+func (r *ConvolutionReverb) Process(input []int16) []int16 {
+	if r.handle == nil || len(input) == 0 {
+		return nil
+	}
+	output := make([]int16, len(input))
+	r.ProcessInto(output, input)
+	return output
+}
+
+// ProcessInto convolves src with the impulse response, writing the result to
+// dst without allocating. It returns len(src), or 0 if dst is shorter than
+// src or the reverb is closed.
+func (r *ConvolutionReverb) ProcessInto(dst, src []int16) int {
+	if r.handle == nil || len(src) == 0 || len(dst) < len(src) {
+		return 0
+	}
+	C.voice_conv_process(r.handle,
+		(*C.short)(unsafe.Pointer(&src[0])),
+		(*C.short)(unsafe.Pointer(&dst[0])),
+		C.int(len(src)))
+	return len(src)
+}
+
+// Latency returns the processing delay in samples introduced by the
+// convolution partitioning.
+func (r *ConvolutionReverb) Latency() int {
+	if r.handle == nil {
+		return 0
+	}
+	return int(C.voice_conv_get_latency(r.handle))
+}
+
+// Close releases the convolution reverb resources.
+func (r *ConvolutionReverb) Close() error {
+	if r.handle != nil {
+		C.voice_conv_destroy(r.handle)
+		r.handle = nil
+		runtime.SetFinalizer(r, nil)
+	}
+	return nil
+}
+
+// effectDefaultMix is the dry/wet balance Delay, Chorus and Flanger start
+// with.
+const effectDefaultMix float32 = 0.5
+
+// delayMaxFeedback is the largest feedback a Delay accepts; at 1 the echoes
+// would never die away.
+const delayMaxFeedback float32 = 0.99
+
 // Delay provides echo/delay effect processing.
 type Delay struct {
-	handle unsafe.Pointer
+	handle         unsafe.Pointer
+	sampleRate     int
+	delayMs        float32
+	feedback       float32
+	feedbackEvents []paramEvent
+	mix            float32
+	dither         DitherType
 }
 
 // NewDelay creates a new delay effect processor.
@@ -86,21 +367,95 @@ type Delay struct {
 // Parameters:
 //   - sampleRate: Audio sample rate in Hz
 //   - delayMs: Delay time in milliseconds
-//   - feedback: Feedback amount (0.0-1.0)
+//   - feedback: Feedback amount (0.0-0.99)
 func NewDelay(sampleRate int, delayMs, feedback float32) (*Delay, error) {
+	if err := validate("delay",
+		checkSampleRate(sampleRate),
+		checkRange("feedback", float64(feedback), 0, float64(delayMaxFeedback)),
+	); err != nil {
+		return nil, err
+	}
+	clearError()
 	handle := C.voice_delay_create(C.int(sampleRate), C.float(delayMs), C.float(feedback))
 	if handle == nil {
-		return nil, errors.New("failed to create delay")
+		return nil, createError("delay")
 	}
-	d := &Delay{handle: handle}
+	d := &Delay{handle: handle, sampleRate: sampleRate, delayMs: delayMs, feedback: feedback, mix: effectDefaultMix}
 	runtime.SetFinalizer(d, (*Delay).Close)
 	return d, nil
 }
 
+// SampleRate returns the sample rate in Hz.
+func (d *Delay) SampleRate() int {
+	return d.sampleRate
+}
+
 // SetDelayTime sets the delay time in milliseconds.
 func (d *Delay) SetDelayTime(ms float32) {
 	if d.handle != nil {
 		C.voice_delay_set_time(d.handle, C.float(ms))
+		d.delayMs = ms
+	}
+}
+
+// DelayTime returns the current delay time in milliseconds.
+func (d *Delay) DelayTime() float32 {
+	return d.delayMs
+}
+
+// NoteDivision is a musical note length used for tempo-synced delays.
+type NoteDivision int
+
+const (
+	// NoteWhole is a whole note (four beats).
+	NoteWhole NoteDivision = 0
+	// NoteHalf is a half note (two beats).
+	NoteHalf NoteDivision = 1
+	// NoteQuarter is a quarter note (one beat).
+	NoteQuarter NoteDivision = 2
+	// NoteEighth is an eighth note (half a beat).
+	NoteEighth NoteDivision = 3
+	// NoteDottedEighth is a dotted eighth note (three quarters of a beat).
+	NoteDottedEighth NoteDivision = 4
+	// NoteEighthTriplet is an eighth-note triplet (a third of a beat).
+	NoteEighthTriplet NoteDivision = 5
+	// NoteSixteenth is a sixteenth note (a quarter of a beat).
+	NoteSixteenth NoteDivision = 6
+)
+
+// beats returns the length of the division in quarter-note beats.
+func (n NoteDivision) beats() float32 {
+	switch n {
+	case NoteWhole:
+		return 4
+	case NoteHalf:
+		return 2
+	case NoteEighth:
+		return 0.5
+	case NoteDottedEighth:
+		return 0.75
+	case NoteEighthTriplet:
+		return 1.0 / 3
+	case NoteSixteenth:
+		return 0.25
+	default:
+		return 1
+	}
+}
+
+// Milliseconds returns the duration of the division at the given tempo.
+func (n NoteDivision) Milliseconds(bpm float32) float32 {
+	if bpm <= 0 {
+		return 0
+	}
+	return 60000 / bpm * n.beats()
+}
+
+// SetDelayTempo sets the delay time to a note division at the given tempo,
+// e.g. SetDelayTempo(120, NoteQuarter) sets 500ms.
+func (d *Delay) SetDelayTempo(bpm float32, division NoteDivision) {
+	if bpm > 0 {
+		d.SetDelayTime(division.Milliseconds(bpm))
 	}
 }
 
@@ -108,22 +463,123 @@ func (d *Delay) SetDelayTime(ms float32) {
 func (d *Delay) SetFeedback(feedback float32) {
 	if d.handle != nil {
 		C.voice_delay_set_feedback(d.handle, C.float(feedback))
+		d.feedback = feedback
+	}
+}
+
+// SetMix sets the dry/wet balance, where 0 is fully dry (the input passes
+// through unchanged) and 1 is fully wet. The default is 0.5. Values outside
+// that range are clamped.
+func (d *Delay) SetMix(wet float32) {
+	if d.handle != nil {
+		wet = float32(math.Max(0, math.Min(1, float64(wet))))
+		C.voice_delay_set_mix(d.handle, C.float(wet))
+		d.mix = wet
 	}
 }
 
+// ScheduleFeedback schedules a feedback change at sampleOffset within the
+// next Process call. The change is interpolated over a few milliseconds to
+// avoid zipper noise. The schedule is cleared after each Process.
+func (d *Delay) ScheduleFeedback(sampleOffset int, value float32) {
+	d.feedbackEvents = scheduleEvent(d.feedbackEvents, sampleOffset, value)
+}
+
 // Process applies delay to the audio.
 func (d *Delay) Process(input []int16) []int16 {
 	if d.handle == nil || len(input) == 0 {
 		return nil
 	}
 	output := make([]int16, len(input))
+	d.ProcessInto(output, input)
+	return output
+}
+
+// ProcessInto applies delay to src, writing the result to dst. It returns
+// len(src), or 0 if dst is shorter than src or the delay is closed.
+// Scheduled feedback changes are applied as in Process. ProcessInto does not
+// allocate unless changes have been scheduled with ScheduleFeedback.
+func (d *Delay) ProcessInto(dst, src []int16) int {
+	if d.handle == nil || len(src) == 0 || len(dst) < len(src) {
+		return 0
+	}
+	dst = dst[:len(src)]
+	if len(d.feedbackEvents) == 0 {
+		d.process(src, dst)
+		return len(src)
+	}
+	d.feedback = processAutomated(src, dst, d.feedbackEvents, d.feedback,
+		func(v float32) { C.voice_delay_set_feedback(d.handle, C.float(v)) },
+		d.process)
+	d.feedbackEvents = d.feedbackEvents[:0]
+	return len(src)
+}
+
+func (d *Delay) process(input, output []int16) {
 	C.voice_delay_process(d.handle,
 		(*C.short)(unsafe.Pointer(&input[0])),
 		(*C.short)(unsafe.Pointer(&output[0])),
 		C.int(len(input)))
+}
+
+// ProcessWetOnly applies delay to the audio and returns only the echoes,
+// with the dry signal removed, for blending in parallel with the original.
+// The mix set with SetMix does not apply, and changes scheduled with
+// ScheduleFeedback apply to Process only.
+func (d *Delay) ProcessWetOnly(input []int16) []int16 {
+	if d.handle == nil || len(input) == 0 {
+		return nil
+	}
+	output := make([]int16, len(input))
+	C.voice_delay_process_wet(d.handle,
+		(*C.short)(unsafe.Pointer(&input[0])),
+		(*C.short)(unsafe.Pointer(&output[0])),
+		C.int(len(input)))
+	return output
+}
+
+// SetStereoSpread sets how far ping-pong echoes are panned toward alternate
+// channels (0.0 = centered, 1.0 = fully left/right).
+func (d *Delay) SetStereoSpread(spread float32) {
+	if d.handle != nil {
+		C.voice_delay_set_stereo_spread(d.handle, C.float(spread))
+	}
+}
+
+// ProcessStereoPingPong applies a ping-pong delay to interleaved stereo audio,
+// where each successive echo alternates between the left and right channels.
+// Returns nil if the input length is not even.
+func (d *Delay) ProcessStereoPingPong(interleaved []int16) []int16 {
+	if d.handle == nil || len(interleaved) == 0 || len(interleaved)%2 != 0 {
+		return nil
+	}
+	output := make([]int16, len(interleaved))
+	C.voice_delay_pingpong(d.handle,
+		(*C.short)(unsafe.Pointer(&interleaved[0])),
+		(*C.short)(unsafe.Pointer(&output[0])),
+		C.int(len(interleaved)/2))
 	return output
 }
 
+// SetDither sets the dither used by ProcessFloat. The default is DitherNone.
+func (d *Delay) SetDither(ditherType DitherType) {
+	d.dither = ditherType
+}
+
+// ProcessFloat applies delay to -1..1 float audio, by way of int16 (see
+// DitherType).
+func (d *Delay) ProcessFloat(input []float32) []float32 {
+	if d.handle == nil || len(input) == 0 {
+		return nil
+	}
+	return int16ToFloat32(d.Process(Float32ToInt16Dithered(input, d.dither)))
+}
+
+// Latency returns 0; the delay processes sample by sample.
+func (d *Delay) Latency() int {
+	return 0
+}
+
 // Close releases the delay resources.
 func (d *Delay) Close() error {
 	if d.handle != nil {
@@ -134,9 +590,21 @@ func (d *Delay) Close() error {
 	return nil
 }
 
+// pitchBlockSize is the number of samples handed to the native pitch
+// shifter per call, matching its internal analysis hop.
+const pitchBlockSize = 256
+
 // PitchShifter provides pitch shifting effect.
+//
+// The native phase vocoder works on fixed blocks, so PitchShifter buffers
+// input internally and only emits whole blocks. Call Flush at end of stream
+// to drain what remains.
 type PitchShifter struct {
-	handle unsafe.Pointer
+	handle     unsafe.Pointer
+	sampleRate int
+	pending    []int16
+	mix        float64      // wet share set by SetMix, or -1 for the native blend
+	dry        *sampleDelay // delays the dry signal by Latency() to match the wet
 }
 
 // NewPitchShifter creates a new pitch shifter.
@@ -145,15 +613,24 @@ type PitchShifter struct {
 //   - sampleRate: Audio sample rate in Hz
 //   - semitones: Pitch shift amount in semitones
 func NewPitchShifter(sampleRate int, semitones float32) (*PitchShifter, error) {
+	if err := validate("pitch shifter", checkSampleRate(sampleRate)); err != nil {
+		return nil, err
+	}
+	clearError()
 	handle := C.voice_pitch_create(C.int(sampleRate), C.float(semitones))
 	if handle == nil {
-		return nil, errors.New("failed to create pitch shifter")
+		return nil, createError("pitch shifter")
 	}
-	p := &PitchShifter{handle: handle}
+	p := &PitchShifter{handle: handle, sampleRate: sampleRate, mix: -1}
 	runtime.SetFinalizer(p, (*PitchShifter).Close)
 	return p, nil
 }
 
+// SampleRate returns the sample rate in Hz.
+func (p *PitchShifter) SampleRate() int {
+	return p.sampleRate
+}
+
 // SetPitch sets the pitch shift amount in semitones.
 func (p *PitchShifter) SetPitch(semitones float32) {
 	if p.handle != nil {
@@ -161,19 +638,108 @@ func (p *PitchShifter) SetPitch(semitones float32) {
 	}
 }
 
+// SetMix sets the dry/wet balance, where 0 is fully dry (the input passes
+// through, delayed by Latency() like the shifted signal) and 1 is fully
+// wet. Intermediate values blend the shifted and original signals for
+// parallel (harmony) effects; the dry signal is delayed to line up with the
+// wet one, so the blend does not comb. Values outside 0-1 are clamped.
+// Until SetMix is called the pitch shifter keeps its built-in blend.
+func (p *PitchShifter) SetMix(wet float32) {
+	if p.handle == nil {
+		return
+	}
+	p.mix = math.Max(0, math.Min(1, float64(wet)))
+	C.voice_pitch_set_mix(p.handle, 1)
+	if latency := p.Latency(); p.dry == nil && latency > 0 {
+		p.dry = &sampleDelay{line: make([]int16, latency)}
+	}
+}
+
+// SetFormantPreserve sets whether the spectral envelope (the formants that
+// give a voice its character) is kept in place while the pitch moves. With
+// it enabled a shifted voice still sounds like the same speaker; disabled,
+// the default, the formants move with the pitch for the familiar chipmunk
+// or giant effect.
+func (p *PitchShifter) SetFormantPreserve(enabled bool) {
+	if p.handle == nil {
+		return
+	}
+	var flag C.int
+	if enabled {
+		flag = 1
+	}
+	C.voice_pitch_set_formant_preserve(p.handle, flag)
+}
+
 // Process applies pitch shifting to the audio.
+//
+// Input of any length is accepted. Samples are buffered until a whole
+// number of blocks is available, so the output may be shorter than the
+// input (or empty); across calls the output keeps pace with the input to
+// within one block. The buffering only affects when samples are returned,
+// not their position in the stream: output sample n always corresponds to
+// input sample n-Latency().
 func (p *PitchShifter) Process(input []int16) []int16 {
 	if p.handle == nil || len(input) == 0 {
 		return nil
 	}
+	p.pending = append(p.pending, input...)
+	n := len(p.pending) / pitchBlockSize * pitchBlockSize
+	if n == 0 {
+		return nil
+	}
+	output := p.processBlocks(p.pending[:n])
+	p.pending = append(p.pending[:0], p.pending[n:]...)
+	return output
+}
+
+// Flush drains the pitch shifter at end of stream, returning the buffered
+// samples followed by the Latency() samples still held inside the native
+// shifter. The total output across Process and Flush is therefore the total
+// input plus Latency() samples.
+func (p *PitchShifter) Flush() []int16 {
+	if p.handle == nil {
+		return nil
+	}
+	want := len(p.pending) + p.Latency()
+	if want == 0 {
+		return nil
+	}
+	padded := (want + pitchBlockSize - 1) / pitchBlockSize * pitchBlockSize
+	input := make([]int16, padded)
+	copy(input, p.pending)
+	p.pending = p.pending[:0]
+	return p.processBlocks(input)[:want]
+}
+
+// processBlocks runs whole blocks through the native shifter.
+func (p *PitchShifter) processBlocks(input []int16) []int16 {
 	output := make([]int16, len(input))
 	C.voice_pitch_process(p.handle,
 		(*C.short)(unsafe.Pointer(&input[0])),
 		(*C.short)(unsafe.Pointer(&output[0])),
 		C.int(len(input)))
+	if p.mix >= 0 {
+		dry := input
+		if p.dry != nil {
+			dry = p.dry.process(input)
+		}
+		for i, s := range dry {
+			output[i] = clampInt16(float64(s) + p.mix*(float64(output[i])-float64(s)))
+		}
+	}
 	return output
 }
 
+// Latency returns the processing delay in samples introduced by the pitch
+// shifter's analysis window.
+func (p *PitchShifter) Latency() int {
+	if p.handle == nil {
+		return 0
+	}
+	return int(C.voice_pitch_get_latency(p.handle))
+}
+
 // Close releases the pitch shifter resources.
 func (p *PitchShifter) Close() error {
 	if p.handle != nil {
@@ -186,7 +752,12 @@ func (p *PitchShifter) Close() error {
 
 // Chorus provides chorus effect processing.
 type Chorus struct {
-	handle unsafe.Pointer
+	handle     unsafe.Pointer
+	sampleRate int
+	depth      float32
+	rate       float32
+	mix        float32
+	dither     DitherType
 }
 
 // NewChorus creates a new chorus effect processor.
@@ -196,19 +767,29 @@ type Chorus struct {
 //   - depth: Modulation depth (0.0-1.0)
 //   - rate: Modulation rate in Hz
 func NewChorus(sampleRate int, depth, rate float32) (*Chorus, error) {
+	if err := validate("chorus", checkSampleRate(sampleRate), checkUnit("depth", depth)); err != nil {
+		return nil, err
+	}
+	clearError()
 	handle := C.voice_chorus_create(C.int(sampleRate), C.float(depth), C.float(rate))
 	if handle == nil {
-		return nil, errors.New("failed to create chorus")
+		return nil, createError("chorus")
 	}
-	c := &Chorus{handle: handle}
+	c := &Chorus{handle: handle, sampleRate: sampleRate, depth: depth, rate: rate, mix: effectDefaultMix}
 	runtime.SetFinalizer(c, (*Chorus).Close)
 	return c, nil
 }
 
+// SampleRate returns the sample rate in Hz.
+func (c *Chorus) SampleRate() int {
+	return c.sampleRate
+}
+
 // SetDepth sets the modulation depth.
 func (c *Chorus) SetDepth(depth float32) {
 	if c.handle != nil {
 		C.voice_chorus_set_depth(c.handle, C.float(depth))
+		c.depth = depth
 	}
 }
 
@@ -216,6 +797,18 @@ func (c *Chorus) SetDepth(depth float32) {
 func (c *Chorus) SetRate(rate float32) {
 	if c.handle != nil {
 		C.voice_chorus_set_rate(c.handle, C.float(rate))
+		c.rate = rate
+	}
+}
+
+// SetMix sets the dry/wet balance, where 0 is fully dry (the input passes
+// through unchanged) and 1 is fully wet. The default is 0.5. Values outside
+// that range are clamped.
+func (c *Chorus) SetMix(wet float32) {
+	if c.handle != nil {
+		wet = float32(math.Max(0, math.Min(1, float64(wet))))
+		C.voice_chorus_set_mix(c.handle, C.float(wet))
+		c.mix = wet
 	}
 }
 
@@ -225,13 +818,58 @@ func (c *Chorus) Process(input []int16) []int16 {
 		return nil
 	}
 	output := make([]int16, len(input))
+	c.ProcessInto(output, input)
+	return output
+}
+
+// ProcessInto applies chorus to src, writing the result to dst without
+// allocating. It returns len(src), or 0 if dst is shorter than src or the
+// chorus is closed.
+func (c *Chorus) ProcessInto(dst, src []int16) int {
+	if c.handle == nil || len(src) == 0 || len(dst) < len(src) {
+		return 0
+	}
 	C.voice_chorus_process(c.handle,
+		(*C.short)(unsafe.Pointer(&src[0])),
+		(*C.short)(unsafe.Pointer(&dst[0])),
+		C.int(len(src)))
+	return len(src)
+}
+
+// ProcessWetOnly applies chorus to the audio and returns only the modulated
+// voices, with the dry signal removed, for blending in parallel with the
+// original. The mix set with SetMix does not apply.
+func (c *Chorus) ProcessWetOnly(input []int16) []int16 {
+	if c.handle == nil || len(input) == 0 {
+		return nil
+	}
+	output := make([]int16, len(input))
+	C.voice_chorus_process_wet(c.handle,
 		(*C.short)(unsafe.Pointer(&input[0])),
 		(*C.short)(unsafe.Pointer(&output[0])),
 		C.int(len(input)))
 	return output
 }
 
+// SetDither sets the dither used by ProcessFloat. The default is DitherNone.
+func (c *Chorus) SetDither(ditherType DitherType) {
+	c.dither = ditherType
+}
+
+// ProcessFloat applies chorus to -1..1 float audio, by way of int16 (see
+// DitherType).
+func (c *Chorus) ProcessFloat(input []float32) []float32 {
+	if c.handle == nil || len(input) == 0 {
+		return nil
+	}
+	return int16ToFloat32(c.Process(Float32ToInt16Dithered(input, c.dither)))
+}
+
+// Latency returns 0; the chorus processes sample by sample.
+func (c *Chorus) Latency() int {
+	return 0
+}
+
 // Close releases the chorus resources.
 func (c *Chorus) Close() error {
 	if c.handle != nil {
@@ -244,7 +882,12 @@ func (c *Chorus) Close() error {
 
 // Flanger provides flanger effect processing.
 type Flanger struct {
-	handle unsafe.Pointer
+	handle     unsafe.Pointer
+	sampleRate int
+	depth      float32
+	rate       float32
+	mix        float32
+	dither     DitherType
 }
 
 // NewFlanger creates a new flanger effect processor.
@@ -254,19 +897,29 @@ type Flanger struct {
 //   - depth: Modulation depth (0.0-1.0)
 //   - rate: Modulation rate in Hz
 func NewFlanger(sampleRate int, depth, rate float32) (*Flanger, error) {
+	if err := validate("flanger", checkSampleRate(sampleRate), checkUnit("depth", depth)); err != nil {
+		return nil, err
+	}
+	clearError()
 	handle := C.voice_flanger_create(C.int(sampleRate), C.float(depth), C.float(rate))
 	if handle == nil {
-		return nil, errors.New("failed to create flanger")
+		return nil, createError("flanger")
 	}
-	f := &Flanger{handle: handle}
+	f := &Flanger{handle: handle, sampleRate: sampleRate, depth: depth, rate: rate, mix: effectDefaultMix}
 	runtime.SetFinalizer(f, (*Flanger).Close)
 	return f, nil
 }
 
+// SampleRate returns the sample rate in Hz.
+func (f *Flanger) SampleRate() int {
+	return f.sampleRate
+}
+
 // SetDepth sets the modulation depth.
 func (f *Flanger) SetDepth(depth float32) {
 	if f.handle != nil {
 		C.voice_flanger_set_depth(f.handle, C.float(depth))
+		f.depth = depth
 	}
 }
 
@@ -274,6 +927,18 @@ func (f *Flanger) SetDepth(depth float32) {
 func (f *Flanger) SetRate(rate float32) {
 	if f.handle != nil {
 		C.voice_flanger_set_rate(f.handle, C.float(rate))
+		f.rate = rate
+	}
+}
+
+// SetMix sets the dry/wet balance, where 0 is fully dry (the input passes
+// through unchanged) and 1 is fully wet. The default is 0.5. Values outside
+// that range are clamped.
+func (f *Flanger) SetMix(wet float32) {
+	if f.handle != nil {
+		wet = float32(math.Max(0, math.Min(1, float64(wet))))
+		C.voice_flanger_set_mix(f.handle, C.float(wet))
+		f.mix = wet
 	}
 }
 
@@ -283,13 +948,58 @@ func (f *Flanger) Process(input []int16) []int16 {
 		return nil
 	}
 	output := make([]int16, len(input))
+	f.ProcessInto(output, input)
+	return output
+}
+
+// ProcessInto applies flanger to src, writing the result to dst without
+// allocating. It returns len(src), or 0 if dst is shorter than src or the
+// flanger is closed.
+func (f *Flanger) ProcessInto(dst, src []int16) int {
+	if f.handle == nil || len(src) == 0 || len(dst) < len(src) {
+		return 0
+	}
 	C.voice_flanger_process(f.handle,
+		(*C.short)(unsafe.Pointer(&src[0])),
+		(*C.short)(unsafe.Pointer(&dst[0])),
+		C.int(len(src)))
+	return len(src)
+}
+
+// ProcessWetOnly applies flanger to the audio and returns only the swept,
+// delayed copy, with the dry signal removed, for blending in parallel with
+// the original. The mix set with SetMix does not apply.
+func (f *Flanger) ProcessWetOnly(input []int16) []int16 {
+	if f.handle == nil || len(input) == 0 {
+		return nil
+	}
+	output := make([]int16, len(input))
+	C.voice_flanger_process_wet(f.handle,
 		(*C.short)(unsafe.Pointer(&input[0])),
 		(*C.short)(unsafe.Pointer(&output[0])),
 		C.int(len(input)))
 	return output
 }
 
+// SetDither sets the dither used by ProcessFloat. The default is DitherNone.
+func (f *Flanger) SetDither(ditherType DitherType) {
+	f.dither = ditherType
+}
+
+// ProcessFloat applies flanger to -1..1 float audio, by way of int16 (see
+// DitherType).
+func (f *Flanger) ProcessFloat(input []float32) []float32 {
+	if f.handle == nil || len(input) == 0 {
+		return nil
+	}
+	return int16ToFloat32(f.Process(Float32ToInt16Dithered(input, f.dither)))
+}
+
+// Latency returns 0; the flanger processes sample by sample.
+func (f *Flanger) Latency() int {
+	return 0
+}
+
 // Close releases the flanger resources.
 func (f *Flanger) Close() error {
 	if f.handle != nil {
@@ -302,7 +1012,9 @@ func (f *Flanger) Close() error {
 
 // TimeStretcher provides time stretching without pitch change.
 type TimeStretcher struct {
-	handle unsafe.Pointer
+	handle     unsafe.Pointer
+	sampleRate int
+	ratio      float32
 }
 
 // NewTimeStretcher creates a new time stretcher.
@@ -311,23 +1023,38 @@ type TimeStretcher struct {
 //   - sampleRate: Audio sample rate in Hz
 //   - ratio: Time stretch ratio (1.0 = no change, 2.0 = half speed)
 func NewTimeStretcher(sampleRate int, ratio float32) (*TimeStretcher, error) {
+	if err := validate("time stretcher", checkSampleRate(sampleRate)); err != nil {
+		return nil, err
+	}
+	clearError()
 	handle := C.voice_time_stretch_create(C.int(sampleRate), C.float(ratio))
 	if handle == nil {
-		return nil, errors.New("failed to create time stretcher")
+		return nil, createError("time stretcher")
 	}
-	t := &TimeStretcher{handle: handle}
+	t := &TimeStretcher{handle: handle, sampleRate: sampleRate, ratio: ratio}
 	runtime.SetFinalizer(t, (*TimeStretcher).Close)
 	return t, nil
 }
 
+// SampleRate returns the sample rate in Hz.
+func (t *TimeStretcher) SampleRate() int {
+	return t.sampleRate
+}
+
 // SetRatio sets the time stretch ratio.
 func (t *TimeStretcher) SetRatio(ratio float32) {
 	if t.handle != nil {
+		t.ratio = ratio
 		C.voice_time_stretch_set_ratio(t.handle, C.float(ratio))
 	}
 }
 
 // Process applies time stretching to the audio.
+//
+// The stretcher buffers input internally for its overlap window, so a call
+// may return fewer samples than len(input) times the ratio (or none at
+// all). The difference is emitted by later calls, and by Flush at end of
+// stream.
 func (t *TimeStretcher) Process(input []int16) []int16 {
 	if t.handle == nil || len(input) == 0 {
 		return nil
@@ -344,6 +1071,32 @@ func (t *TimeStretcher) Process(input []int16) []int16 {
 	return output[:actualLen]
 }
 
+// Flush emits the output still buffered inside the stretcher at end of
+// stream and resets it for reuse. After Flush, the total output across all
+// Process calls is approximately the total input times the ratio.
+func (t *TimeStretcher) Flush() []int16 {
+	if t.handle == nil {
+		return nil
+	}
+	// The buffered input is at most one overlap window
+	capacity := int(float32(t.Latency()+1)*t.ratio) + 1024
+	output := make([]int16, capacity)
+	outputLen := C.int(capacity)
+	C.voice_time_stretch_flush(t.handle,
+		(*C.short)(unsafe.Pointer(&output[0])),
+		&outputLen)
+	return output[:outputLen]
+}
+
+// Latency returns the processing delay in input samples introduced by the
+// time stretcher's overlap window.
+func (t *TimeStretcher) Latency() int {
+	if t.handle == nil {
+		return 0
+	}
+	return int(C.voice_time_stretch_get_latency(t.handle))
+}
+
 // Close releases the time stretcher resources.
 func (t *TimeStretcher) Close() error {
 	if t.handle != nil {
@@ -354,30 +1107,193 @@ func (t *TimeStretcher) Close() error {
 	return nil
 }
 
-// WatermarkEmbedder embeds audio watermarks.
-type WatermarkEmbedder struct {
-	handle unsafe.Pointer
+// WarpProcessor changes tempo and pitch independently in a single pass,
+// avoiding the compounded artifacts of chaining TimeStretcher and
+// PitchShifter. It is intended for offline processing of whole buffers.
+type WarpProcessor struct {
+	handle     unsafe.Pointer
+	sampleRate int
+	timeRatio  float32
 }
 
-// NewWatermarkEmbedder creates a new watermark embedder.
+// NewWarpProcessor creates a new warp processor with no time or pitch
+// change.
 //
 // Parameters:
 //   - sampleRate: Audio sample rate in Hz
-//   - strength: Watermark strength (0.0-1.0)
-func NewWatermarkEmbedder(sampleRate int, strength float32) (*WatermarkEmbedder, error) {
-	handle := C.voice_watermark_embedder_create(C.int(sampleRate), C.float(strength))
+func NewWarpProcessor(sampleRate int) (*WarpProcessor, error) {
+	if err := validate("warp processor", checkSampleRate(sampleRate)); err != nil {
+		return nil, err
+	}
+	clearError()
+	handle := C.voice_warp_create(C.int(sampleRate))
 	if handle == nil {
-		return nil, errors.New("failed to create watermark embedder")
+		return nil, createError("warp processor")
 	}
-	w := &WatermarkEmbedder{handle: handle}
-	runtime.SetFinalizer(w, (*WatermarkEmbedder).Close)
+	w := &WarpProcessor{handle: handle, sampleRate: sampleRate, timeRatio: 1}
+	runtime.SetFinalizer(w, (*WarpProcessor).Close)
 	return w, nil
 }
 
-// Embed embeds a watermark payload into the audio.
-func (w *WatermarkEmbedder) Embed(input []int16, payload []byte) []int16 {
-	if w.handle == nil || len(input) == 0 || len(payload) == 0 {
-		return nil
+// SampleRate returns the sample rate in Hz.
+func (w *WarpProcessor) SampleRate() int {
+	return w.sampleRate
+}
+
+// SetTimeRatio sets the duration ratio (1.0 = no change, 2.0 = twice as
+// long, i.e. half speed).
+func (w *WarpProcessor) SetTimeRatio(ratio float32) {
+	if w.handle != nil && ratio > 0 {
+		w.timeRatio = ratio
+		C.voice_warp_set_time_ratio(w.handle, C.float(ratio))
+	}
+}
+
+// SetPitchSemitones sets the pitch shift in semitones.
+func (w *WarpProcessor) SetPitchSemitones(semitones float32) {
+	if w.handle != nil {
+		C.voice_warp_set_pitch(w.handle, C.float(semitones))
+	}
+}
+
+// ProcessAll warps a complete buffer. The output is approximately
+// len(input) times the time ratio samples long.
+func (w *WarpProcessor) ProcessAll(input []int16) []int16 {
+	if w.handle == nil || len(input) == 0 {
+		return nil
+	}
+	// Leave headroom for the final partial analysis frame
+	capacity := int(float32(len(input))*w.timeRatio) + 4096
+	output := make([]int16, capacity)
+	outputLen := C.int(capacity)
+	C.voice_warp_process(w.handle,
+		(*C.short)(unsafe.Pointer(&input[0])),
+		C.int(len(input)),
+		(*C.short)(unsafe.Pointer(&output[0])),
+		&outputLen)
+	return output[:outputLen]
+}
+
+// Close releases the warp processor resources.
+func (w *WarpProcessor) Close() error {
+	if w.handle != nil {
+		C.voice_warp_destroy(w.handle)
+		w.handle = nil
+		runtime.SetFinalizer(w, nil)
+	}
+	return nil
+}
+
+// WatermarkEmbedder embeds audio watermarks.
+type WatermarkEmbedder struct {
+	handle       unsafe.Pointer
+	sampleRate   int
+	maxPayload   int
+	payloadLimit int // set by SetMaxPayloadLen, 0 if unset
+}
+
+// NewWatermarkEmbedder creates a new watermark embedder.
+//
+// Parameters:
+//   - sampleRate: Audio sample rate in Hz
+//   - strength: Watermark strength (0.0-1.0)
+func NewWatermarkEmbedder(sampleRate int, strength float32) (*WatermarkEmbedder, error) {
+	if err := validate("watermark embedder",
+		checkSampleRate(sampleRate),
+		checkUnit("strength", strength),
+	); err != nil {
+		return nil, err
+	}
+	clearError()
+	handle := C.voice_watermark_embedder_create(C.int(sampleRate), C.float(strength))
+	if handle == nil {
+		return nil, createError("watermark embedder")
+	}
+	w := &WatermarkEmbedder{handle: handle, sampleRate: sampleRate}
+	w.updateMaxPayload()
+	runtime.SetFinalizer(w, (*WatermarkEmbedder).Close)
+	return w, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (w *WatermarkEmbedder) SampleRate() int {
+	return w.sampleRate
+}
+
+// SetStrength changes the watermark strength (0.0-1.0) for subsequent
+// embeds. Stronger watermarks survive more processing but are more likely
+// to be audible; Capacity and MaxPayloadLen may change with the strength.
+func (w *WatermarkEmbedder) SetStrength(strength float32) error {
+	if w.handle == nil {
+		return errors.New("watermark embedder is closed")
+	}
+	if err := checkUnit("strength", strength); err != nil {
+		return err
+	}
+	C.voice_watermark_embedder_set_strength(w.handle, C.float(strength))
+	w.updateMaxPayload()
+	return nil
+}
+
+// updateMaxPayload re-reads the native maximum payload, which depends on
+// the strength, and applies any limit set with SetMaxPayloadLen.
+func (w *WatermarkEmbedder) updateMaxPayload() {
+	w.maxPayload = int(C.voice_watermark_embedder_max_payload(w.handle))
+	if w.payloadLimit > 0 {
+		w.maxPayload = min(w.maxPayload, w.payloadLimit)
+	}
+}
+
+// MaxPayloadLen returns the largest payload, in bytes, Embed accepts in one
+// call, however long the input. It defaults to the most the native
+// embedder supports at the current strength.
+func (w *WatermarkEmbedder) MaxPayloadLen() int {
+	return w.maxPayload
+}
+
+// SetMaxPayloadLen lowers the largest payload Embed accepts, for instance
+// to match what a deployed WatermarkDetector can extract. n must be
+// between 1 and the native maximum at the current strength; the limit is
+// kept if SetStrength later changes the native maximum.
+func (w *WatermarkEmbedder) SetMaxPayloadLen(n int) error {
+	if w.handle == nil {
+		return errors.New("watermark embedder is closed")
+	}
+	limit := int(C.voice_watermark_embedder_max_payload(w.handle))
+	if err := checkRange("max payload", float64(n), 1, float64(limit)); err != nil {
+		return err
+	}
+	w.payloadLimit = n
+	w.maxPayload = n
+	return nil
+}
+
+// Capacity returns how many payload bytes fit in numSamples samples at the
+// current strength.
+func (w *WatermarkEmbedder) Capacity(numSamples int) int {
+	if w.handle == nil || numSamples <= 0 {
+		return 0
+	}
+	return int(C.voice_watermark_capacity(w.handle, C.int(numSamples)))
+}
+
+// Embed embeds a watermark payload into the audio.
+// Returns an error if the payload is longer than MaxPayloadLen or does not
+// fit in the input (see Capacity).
+func (w *WatermarkEmbedder) Embed(input []int16, payload []byte) ([]int16, error) {
+	if w.handle == nil {
+		return nil, errors.New("watermark embedder is closed")
+	}
+	if len(input) == 0 || len(payload) == 0 {
+		return nil, nil
+	}
+	if len(payload) > w.maxPayload {
+		return nil, fmt.Errorf("%w: watermark payload of %d bytes exceeds maximum of %d bytes",
+			ErrInvalidRange, len(payload), w.maxPayload)
+	}
+	if capacity := w.Capacity(len(input)); len(payload) > capacity {
+		return nil, fmt.Errorf("watermark payload of %d bytes exceeds capacity of %d bytes for %d samples",
+			len(payload), capacity, len(input))
 	}
 	output := make([]int16, len(input))
 	C.voice_watermark_embed(w.handle,
@@ -386,9 +1302,93 @@ func (w *WatermarkEmbedder) Embed(input []int16, payload []byte) []int16 {
 		C.int(len(input)),
 		(*C.uchar)(unsafe.Pointer(&payload[0])),
 		C.int(len(payload)))
+	return output, nil
+}
+
+// EmbedStreaming embeds as much of payload as fits in input, starting at
+// byte offset, so a long payload can be spread across consecutive blocks.
+//
+// Returns the watermarked audio and the offset to pass with the next block.
+// The payload is fully embedded once next == len(payload); blocks after that
+// are returned unmodified. Like Embed, it returns an error if the payload is
+// longer than MaxPayloadLen.
+func (w *WatermarkEmbedder) EmbedStreaming(input []int16, payload []byte, offset int) (output []int16, next int, err error) {
+	if offset < 0 || offset > len(payload) {
+		return nil, offset, fmt.Errorf("payload offset %d out of range [0, %d]", offset, len(payload))
+	}
+	if len(payload) > w.maxPayload {
+		return nil, offset, fmt.Errorf("%w: watermark payload of %d bytes exceeds maximum of %d bytes",
+			ErrInvalidRange, len(payload), w.maxPayload)
+	}
+	if offset == len(payload) {
+		output = make([]int16, len(input))
+		copy(output, input)
+		return output, offset, nil
+	}
+	end := offset + w.Capacity(len(input))
+	if end > len(payload) {
+		end = len(payload)
+	}
+	if end == offset {
+		return nil, offset, fmt.Errorf("block of %d samples is too short to carry any payload", len(input))
+	}
+	output, err = w.Embed(input, payload[offset:end])
+	if err != nil {
+		return nil, offset, err
+	}
+	return output, end, nil
+}
+
+// EmbedRepeating embeds payload afresh at the start of every periodSamples
+// block of input, so that any excerpt at least periodSamples long still
+// carries a whole copy; recover it with WatermarkDetector.DetectRepeating.
+// A final block too short for the payload is left unmarked. Returns nil if
+// the embedder is closed, or payload is longer than MaxPayloadLen or does
+// not fit in one period.
+func (w *WatermarkEmbedder) EmbedRepeating(input []int16, payload []byte, periodSamples int) []int16 {
+	if periodSamples <= 0 || len(payload) == 0 ||
+		len(payload) > min(w.maxPayload, w.Capacity(periodSamples)) {
+		return nil
+	}
+	output := make([]int16, len(input))
+	for start := 0; start < len(input); start += periodSamples {
+		block := input[start:min(start+periodSamples, len(input))]
+		marked, err := w.Embed(block, payload)
+		if err != nil {
+			marked = block
+		}
+		copy(output[start:], marked)
+	}
+	return output
+}
+
+// watermarkCopies is how many times EmbedString repeats its payload, so
+// that DetectString can outvote bit errors in any one copy.
+const watermarkCopies = 3
+
+// EmbedString embeds a text payload, such as a user or session ID, with
+// error correction so that DetectString can recover it after lossy coding
+// or added noise. The string and a CRC-32 of it are embedded three times,
+// so it needs 3*(len(s)+4) bytes of Capacity. Returns nil if the embedder
+// is closed, s is empty, or the encoded string does not fit in input.
+func (w *WatermarkEmbedder) EmbedString(input []int16, s string) []int16 {
+	if s == "" {
+		return nil
+	}
+	output, err := w.Embed(input, encodeWatermarkString(s))
+	if err != nil {
+		return nil
+	}
 	return output
 }
 
+// encodeWatermarkString returns s followed by its CRC-32, repeated
+// watermarkCopies times.
+func encodeWatermarkString(s string) []byte {
+	one := binary.LittleEndian.AppendUint32([]byte(s), crc32.ChecksumIEEE([]byte(s)))
+	return bytes.Repeat(one, watermarkCopies)
+}
+
 // Close releases the embedder resources.
 func (w *WatermarkEmbedder) Close() error {
 	if w.handle != nil {
@@ -401,39 +1401,145 @@ func (w *WatermarkEmbedder) Close() error {
 
 // WatermarkDetector detects audio watermarks.
 type WatermarkDetector struct {
-	handle unsafe.Pointer
+	handle     unsafe.Pointer
+	sampleRate int
 }
 
 // NewWatermarkDetector creates a new watermark detector.
 func NewWatermarkDetector(sampleRate int) (*WatermarkDetector, error) {
+	if err := validate("watermark detector", checkSampleRate(sampleRate)); err != nil {
+		return nil, err
+	}
+	clearError()
 	handle := C.voice_watermark_detector_create(C.int(sampleRate))
 	if handle == nil {
-		return nil, errors.New("failed to create watermark detector")
+		return nil, createError("watermark detector")
 	}
-	d := &WatermarkDetector{handle: handle}
+	d := &WatermarkDetector{handle: handle, sampleRate: sampleRate}
 	runtime.SetFinalizer(d, (*WatermarkDetector).Close)
 	return d, nil
 }
 
+// SampleRate returns the sample rate in Hz.
+func (d *WatermarkDetector) SampleRate() int {
+	return d.sampleRate
+}
+
+// MaxPayloadLen returns the largest payload, in bytes, the detector can
+// extract.
+func (d *WatermarkDetector) MaxPayloadLen() int {
+	if d.handle == nil {
+		return 0
+	}
+	return int(C.voice_watermark_detector_max_payload(d.handle))
+}
+
 // Detect attempts to detect and extract a watermark from the audio.
 // Returns the extracted payload and a confidence score (0.0-1.0).
 func (d *WatermarkDetector) Detect(input []int16) ([]byte, float32) {
 	if d.handle == nil || len(input) == 0 {
 		return nil, 0
 	}
-	payload := make([]byte, 256) // Max payload size
-	var payloadLen C.int
-	var confidence C.float
+	payload := make([]byte, d.MaxPayloadLen())
+	n, confidence, err := d.DetectInto(input, payload)
+	if err != nil || n == 0 {
+		return nil, 0
+	}
+	return payload[:n], confidence
+}
+
+// DetectInto detects a watermark and writes its payload into the
+// caller-supplied buffer. The watermark may start anywhere within the first
+// symbol (bit period) of input.
+//
+// Returns the payload length and a confidence score (0.0-1.0). If the
+// detected payload is longer than payload, an error is returned and n
+// reports the required buffer size.
+func (d *WatermarkDetector) DetectInto(input []int16, payload []byte) (n int, confidence float32, err error) {
+	if d.handle == nil {
+		return 0, 0, errors.New("watermark detector is closed")
+	}
+	if len(input) == 0 || len(payload) == 0 {
+		return 0, 0, nil
+	}
+	// payloadLen is in/out: buffer capacity in, detected length out
+	payloadLen := C.int(len(payload))
+	var conf C.float
 	C.voice_watermark_detect(d.handle,
 		(*C.short)(unsafe.Pointer(&input[0])),
 		C.int(len(input)),
 		(*C.uchar)(unsafe.Pointer(&payload[0])),
 		&payloadLen,
-		&confidence)
-	if payloadLen == 0 {
+		&conf)
+	if int(payloadLen) > len(payload) {
+		return int(payloadLen), float32(conf),
+			fmt.Errorf("watermark payload of %d bytes exceeds buffer of %d bytes", int(payloadLen), len(payload))
+	}
+	return int(payloadLen), float32(conf), nil
+}
+
+// DetectRepeating detects a watermark embedded by EmbedRepeating with the
+// same periodSamples, in audio that may have been cropped anywhere. The
+// detector locks on to a watermark that starts anywhere within its first
+// symbol, so DetectRepeating only needs to try one offset per symbol of the
+// period, and returns the payload found with the highest confidence.
+func (d *WatermarkDetector) DetectRepeating(input []int16, periodSamples int) ([]byte, float32) {
+	if d.handle == nil || periodSamples <= 0 {
 		return nil, 0
 	}
-	return payload[:payloadLen], float32(confidence)
+	step := max(1, int(C.voice_watermark_detector_symbol_len(d.handle)))
+	var best []byte
+	var bestConfidence float32
+	buf := make([]byte, d.MaxPayloadLen())
+	for offset := 0; offset < min(periodSamples, len(input)); offset += step {
+		window := input[offset:min(offset+periodSamples+step, len(input))]
+		n, confidence, err := d.DetectInto(window, buf)
+		if err == nil && n > 0 && confidence > bestConfidence {
+			best, bestConfidence = append(best[:0], buf[:n]...), confidence
+		}
+	}
+	return best, bestConfidence
+}
+
+// DetectString detects a watermark embedded by EmbedString and returns the
+// string and a confidence score (0.0-1.0). Each bit is decided by majority
+// vote across the embedded copies, which corrects scattered bit errors; if
+// the result still fails its CRC, DetectString returns "", 0.
+func (d *WatermarkDetector) DetectString(input []int16) (string, float32) {
+	payload, confidence := d.Detect(input)
+	s, ok := decodeWatermarkString(payload)
+	if !ok {
+		return "", 0
+	}
+	return s, confidence
+}
+
+// decodeWatermarkString reverses encodeWatermarkString, reporting whether
+// the voted copy passed its CRC.
+func decodeWatermarkString(payload []byte) (string, bool) {
+	size := len(payload) / watermarkCopies
+	if size <= crc32.Size || len(payload)%watermarkCopies != 0 {
+		return "", false
+	}
+	voted := make([]byte, size)
+	for i := range voted {
+		for bit := byte(1); bit != 0; bit <<= 1 {
+			votes := 0
+			for c := 0; c < watermarkCopies; c++ {
+				if payload[c*size+i]&bit != 0 {
+					votes++
+				}
+			}
+			if 2*votes > watermarkCopies {
+				voted[i] |= bit
+			}
+		}
+	}
+	text := voted[:size-crc32.Size]
+	if crc32.ChecksumIEEE(text) != binary.LittleEndian.Uint32(voted[size-crc32.Size:]) {
+		return "", false
+	}
+	return string(text), true
 }
 
 // Close releases the detector resources.
@@ -445,3 +1551,424 @@ func (d *WatermarkDetector) Close() error {
 	}
 	return nil
 }
+
+// StereoWidth widens or narrows the stereo image using mid-side processing.
+type StereoWidth struct {
+	handle     unsafe.Pointer
+	sampleRate int
+}
+
+// NewStereoWidth creates a new stereo width processor.
+func NewStereoWidth(sampleRate int) (*StereoWidth, error) {
+	if err := validate("stereo width processor", checkSampleRate(sampleRate)); err != nil {
+		return nil, err
+	}
+	clearError()
+	handle := C.voice_stereo_width_create(C.int(sampleRate))
+	if handle == nil {
+		return nil, createError("stereo width processor")
+	}
+	s := &StereoWidth{handle: handle, sampleRate: sampleRate}
+	runtime.SetFinalizer(s, (*StereoWidth).Close)
+	return s, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (s *StereoWidth) SampleRate() int {
+	return s.sampleRate
+}
+
+// SetWidth sets the stereo width (1.0 = unchanged, 0.0 = mono, >1.0 = wider).
+func (s *StereoWidth) SetWidth(width float32) {
+	if s.handle != nil {
+		C.voice_stereo_width_set_width(s.handle, C.float(width))
+	}
+}
+
+// Process applies the width adjustment to interleaved stereo audio.
+// Returns nil if the input length is not even.
+func (s *StereoWidth) Process(interleaved []int16) []int16 {
+	if s.handle == nil || len(interleaved) == 0 || len(interleaved)%2 != 0 {
+		return nil
+	}
+	output := make([]int16, len(interleaved))
+	C.voice_stereo_width_process(s.handle,
+		(*C.short)(unsafe.Pointer(&interleaved[0])),
+		(*C.short)(unsafe.Pointer(&output[0])),
+		C.int(len(interleaved)/2))
+	return output
+}
+
+// Close releases the stereo width processor resources.
+func (s *StereoWidth) Close() error {
+	if s.handle != nil {
+		C.voice_stereo_width_destroy(s.handle)
+		s.handle = nil
+		runtime.SetFinalizer(s, nil)
+	}
+	return nil
+}
+
+// BitCrusher reduces bit depth and sample rate for lo-fi effects.
+type BitCrusher struct {
+	handle     unsafe.Pointer
+	sampleRate int
+}
+
+// NewBitCrusher creates a new bit crusher.
+//
+// Parameters:
+//   - sampleRate: Audio sample rate in Hz
+//   - bits: Output bit depth (clamped to 1-16)
+//   - downsampleFactor: Sample-and-hold factor (1 = no downsampling)
+func NewBitCrusher(sampleRate int, bits int, downsampleFactor int) (*BitCrusher, error) {
+	if err := validate("bit crusher", checkSampleRate(sampleRate)); err != nil {
+		return nil, err
+	}
+	clearError()
+	handle := C.voice_bitcrush_create(C.int(sampleRate),
+		C.int(clampBits(bits)), C.int(clampDownsample(downsampleFactor)))
+	if handle == nil {
+		return nil, createError("bit crusher")
+	}
+	b := &BitCrusher{handle: handle, sampleRate: sampleRate}
+	runtime.SetFinalizer(b, (*BitCrusher).Close)
+	return b, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (b *BitCrusher) SampleRate() int {
+	return b.sampleRate
+}
+
+func clampBits(bits int) int {
+	if bits < 1 {
+		return 1
+	}
+	if bits > 16 {
+		return 16
+	}
+	return bits
+}
+
+func clampDownsample(factor int) int {
+	if factor < 1 {
+		return 1
+	}
+	return factor
+}
+
+// SetBits sets the output bit depth, clamped to 1-16.
+func (b *BitCrusher) SetBits(bits int) {
+	if b.handle != nil {
+		C.voice_bitcrush_set_bits(b.handle, C.int(clampBits(bits)))
+	}
+}
+
+// SetDownsample sets the sample-and-hold factor (1 = no downsampling).
+func (b *BitCrusher) SetDownsample(factor int) {
+	if b.handle != nil {
+		C.voice_bitcrush_set_downsample(b.handle, C.int(clampDownsample(factor)))
+	}
+}
+
+// Process applies bit crushing to the audio.
+func (b *BitCrusher) Process(input []int16) []int16 {
+	if b.handle == nil || len(input) == 0 {
+		return nil
+	}
+	output := make([]int16, len(input))
+	b.ProcessInto(output, input)
+	return output
+}
+
+// ProcessInto applies bit crushing to src, writing the result to dst without
+// allocating. It returns len(src), or 0 if dst is shorter than src or the
+// bit crusher is closed.
+func (b *BitCrusher) ProcessInto(dst, src []int16) int {
+	if b.handle == nil || len(src) == 0 || len(dst) < len(src) {
+		return 0
+	}
+	C.voice_bitcrush_process(b.handle,
+		(*C.short)(unsafe.Pointer(&src[0])),
+		(*C.short)(unsafe.Pointer(&dst[0])),
+		C.int(len(src)))
+	return len(src)
+}
+
+// Close releases the bit crusher resources.
+func (b *BitCrusher) Close() error {
+	if b.handle != nil {
+		C.voice_bitcrush_destroy(b.handle)
+		b.handle = nil
+		runtime.SetFinalizer(b, nil)
+	}
+	return nil
+}
+
+// RingModulator multiplies the audio by a sine carrier, replacing each
+// input frequency f with the sum and difference frequencies f+carrier and
+// f-carrier for metallic, robotic and bell-like textures.
+type RingModulator struct {
+	handle     unsafe.Pointer
+	sampleRate int
+}
+
+// NewRingModulator creates a new ring modulator.
+//
+// Parameters:
+//   - sampleRate: Audio sample rate in Hz
+//   - carrierHz: Carrier frequency in Hz; tens of Hz give a robotic buzz,
+//     hundreds a metallic, inharmonic tone
+func NewRingModulator(sampleRate int, carrierHz float32) (*RingModulator, error) {
+	if err := validate("ring modulator",
+		checkSampleRate(sampleRate),
+		checkRange("carrier frequency", float64(carrierHz), 0, float64(sampleRate)/2),
+	); err != nil {
+		return nil, err
+	}
+	clearError()
+	handle := C.voice_ringmod_create(C.int(sampleRate), C.float(carrierHz))
+	if handle == nil {
+		return nil, createError("ring modulator")
+	}
+	r := &RingModulator{handle: handle, sampleRate: sampleRate}
+	runtime.SetFinalizer(r, (*RingModulator).Close)
+	return r, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (r *RingModulator) SampleRate() int {
+	return r.sampleRate
+}
+
+// SetFrequency sets the carrier frequency in Hz. The carrier phase is
+// continuous, so the frequency can be swept without clicks.
+func (r *RingModulator) SetFrequency(hz float32) {
+	if r.handle != nil {
+		C.voice_ringmod_set_frequency(r.handle, C.float(hz))
+	}
+}
+
+// SetMix sets the dry/wet balance, where 0 is fully dry (the input passes
+// through unchanged) and 1, the default, is pure ring modulation.
+func (r *RingModulator) SetMix(wet float32) {
+	if r.handle != nil {
+		C.voice_ringmod_set_mix(r.handle, C.float(wet))
+	}
+}
+
+// Process applies ring modulation to the audio.
+func (r *RingModulator) Process(input []int16) []int16 {
+	if r.handle == nil || len(input) == 0 {
+		return nil
+	}
+	output := make([]int16, len(input))
+	r.ProcessInto(output, input)
+	return output
+}
+
+// ProcessInto applies ring modulation to src, writing the result to dst
+// without allocating. It returns len(src), or 0 if dst is shorter than src
+// or the modulator is closed. The modulator works sample by sample, so dst
+// may be src itself.
+func (r *RingModulator) ProcessInto(dst, src []int16) int {
+	if r.handle == nil || len(src) == 0 || len(dst) < len(src) {
+		return 0
+	}
+	C.voice_ringmod_process(r.handle,
+		(*C.short)(unsafe.Pointer(&src[0])),
+		(*C.short)(unsafe.Pointer(&dst[0])),
+		C.int(len(src)))
+	return len(src)
+}
+
+// Latency returns 0; the ring modulator processes sample by sample.
+func (r *RingModulator) Latency() int {
+	return 0
+}
+
+// Close releases the ring modulator resources.
+func (r *RingModulator) Close() error {
+	if r.handle != nil {
+		C.voice_ringmod_destroy(r.handle)
+		r.handle = nil
+		runtime.SetFinalizer(r, nil)
+	}
+	return nil
+}
+
+// AutoWah is an envelope filter: a resonant band-pass whose center
+// frequency follows the level of the input, opening toward maxHz on loud
+// notes and closing toward minHz as they decay.
+type AutoWah struct {
+	handle     unsafe.Pointer
+	sampleRate int
+}
+
+// NewAutoWah creates a new auto-wah.
+//
+// Parameters:
+//   - sampleRate: Audio sample rate in Hz
+//   - sensitivity: How strongly the input level drives the sweep
+//     (0.0-1.0); higher values reach maxHz on quieter playing
+//   - minHz: Center frequency at silence
+//   - maxHz: Center frequency at full sweep
+//   - q: Filter resonance (e.g. 2-8; higher is more vocal)
+func NewAutoWah(sampleRate int, sensitivity, minHz, maxHz, q float32) (*AutoWah, error) {
+	if err := validate("auto-wah",
+		checkSampleRate(sampleRate),
+		checkUnit("sensitivity", sensitivity),
+		checkRange("minimum frequency", float64(minHz), 1, float64(maxHz)),
+		checkRange("maximum frequency", float64(maxHz), float64(minHz), float64(sampleRate)/2),
+		checkRange("Q", float64(q), 0.1, 100),
+	); err != nil {
+		return nil, err
+	}
+	clearError()
+	handle := C.voice_autowah_create(C.int(sampleRate), C.float(sensitivity),
+		C.float(minHz), C.float(maxHz), C.float(q))
+	if handle == nil {
+		return nil, createError("auto-wah")
+	}
+	w := &AutoWah{handle: handle, sampleRate: sampleRate}
+	runtime.SetFinalizer(w, (*AutoWah).Close)
+	return w, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (w *AutoWah) SampleRate() int {
+	return w.sampleRate
+}
+
+// SetSensitivity sets how strongly the input level drives the sweep
+// (0.0-1.0).
+func (w *AutoWah) SetSensitivity(sensitivity float32) {
+	if w.handle != nil {
+		C.voice_autowah_set_sensitivity(w.handle, C.float(sensitivity))
+	}
+}
+
+// Process applies the auto-wah to the audio.
+func (w *AutoWah) Process(input []int16) []int16 {
+	if w.handle == nil || len(input) == 0 {
+		return nil
+	}
+	output := make([]int16, len(input))
+	w.ProcessInto(output, input)
+	return output
+}
+
+// ProcessInto applies the auto-wah to src, writing the result to dst without
+// allocating. It returns len(src), or 0 if dst is shorter than src or the
+// auto-wah is closed.
+func (w *AutoWah) ProcessInto(dst, src []int16) int {
+	if w.handle == nil || len(src) == 0 || len(dst) < len(src) {
+		return 0
+	}
+	C.voice_autowah_process(w.handle,
+		(*C.short)(unsafe.Pointer(&src[0])),
+		(*C.short)(unsafe.Pointer(&dst[0])),
+		C.int(len(src)))
+	return len(src)
+}
+
+// CenterHz returns the filter center frequency at the end of the most
+// recent Process call, for metering or driving a UI.
+func (w *AutoWah) CenterHz() float32 {
+	if w.handle == nil {
+		return 0
+	}
+	return float32(C.voice_autowah_get_center(w.handle))
+}
+
+// Latency returns 0; the auto-wah processes sample by sample.
+func (w *AutoWah) Latency() int {
+	return 0
+}
+
+// Close releases the auto-wah resources.
+func (w *AutoWah) Close() error {
+	if w.handle != nil {
+		C.voice_autowah_destroy(w.handle)
+		w.handle = nil
+		runtime.SetFinalizer(w, nil)
+	}
+	return nil
+}
+
+// SubHarmonic synthesizes a sub-octave, a tone one octave below the
+// fundamental of the input, for bass enhancement. The input should be
+// monophonic, such as a bass line or a voice; chords confuse the tracking.
+type SubHarmonic struct {
+	handle     unsafe.Pointer
+	sampleRate int
+}
+
+// NewSubHarmonic creates a new sub-harmonic generator with an even blend
+// of the input and the sub-octave.
+func NewSubHarmonic(sampleRate int) (*SubHarmonic, error) {
+	if err := validate("sub-harmonic generator", checkSampleRate(sampleRate)); err != nil {
+		return nil, err
+	}
+	clearError()
+	handle := C.voice_subharmonic_create(C.int(sampleRate))
+	if handle == nil {
+		return nil, createError("sub-harmonic generator")
+	}
+	s := &SubHarmonic{handle: handle, sampleRate: sampleRate}
+	runtime.SetFinalizer(s, (*SubHarmonic).Close)
+	return s, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (s *SubHarmonic) SampleRate() int {
+	return s.sampleRate
+}
+
+// SetMix sets the balance between the input and the sub-octave, where 0
+// is the input only and 1 the sub-octave only. The default is 0.5.
+func (s *SubHarmonic) SetMix(wet float32) {
+	if s.handle != nil {
+		C.voice_subharmonic_set_mix(s.handle, C.float(wet))
+	}
+}
+
+// Process adds the sub-octave to the audio.
+func (s *SubHarmonic) Process(input []int16) []int16 {
+	if s.handle == nil || len(input) == 0 {
+		return nil
+	}
+	output := make([]int16, len(input))
+	s.ProcessInto(output, input)
+	return output
+}
+
+// ProcessInto adds the sub-octave to src, writing the result to dst without
+// allocating. It returns len(src), or 0 if dst is shorter than src or the
+// generator is closed.
+func (s *SubHarmonic) ProcessInto(dst, src []int16) int {
+	if s.handle == nil || len(src) == 0 || len(dst) < len(src) {
+		return 0
+	}
+	C.voice_subharmonic_process(s.handle,
+		(*C.short)(unsafe.Pointer(&src[0])),
+		(*C.short)(unsafe.Pointer(&dst[0])),
+		C.int(len(src)))
+	return len(src)
+}
+
+// Latency returns 0; the generator processes sample by sample.
+func (s *SubHarmonic) Latency() int {
+	return 0
+}
+
+// Close releases the sub-harmonic generator resources.
+func (s *SubHarmonic) Close() error {
+	if s.handle != nil {
+		C.voice_subharmonic_destroy(s.handle)
+		s.handle = nil
+		runtime.SetFinalizer(s, nil)
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package sonickit
 
 import (
+	"math"
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -26,6 +28,68 @@ func TestReverb(t *testing.T) {
 	assert.Len(t, output, len(input))
 }
 
+func TestReverbControls(t *testing.T) {
+	reverb, err := NewReverb(48000, 0.5, 0.5)
+	require.NoError(t, err)
+	defer reverb.Close()
+
+	input := Sine(48000, 440, 0.3, 4800)
+	for name, set := range map[string]func(){
+		"early reflections": func() { reverb.SetEarlyReflectionsLevel(0.9) },
+		"decay time":        func() { reverb.SetDecayTime(4) },
+		"damping":           func() { reverb.SetDamping(0.1) },
+		"pre-delay":         func() { reverb.SetPreDelay(40) },
+	} {
+		set()
+		assert.Len(t, reverb.Process(input), len(input), name)
+	}
+}
+
+func TestReverbDecayTime(t *testing.T) {
+	// A 10ms burst, then the tail 0.5-1s later
+	tail := func(seconds float32) float32 {
+		reverb, err := NewReverb(48000, 0.5, 1)
+		require.NoError(t, err)
+		defer reverb.Close()
+		reverb.SetDecayTime(seconds)
+
+		input := make([]int16, 48000)
+		copy(input, Sine(48000, 440, 0.5, 480))
+		output := reverb.Process(input)
+		require.Len(t, output, len(input))
+		return rmsDbfs(output[24000:])
+	}
+	short, long := tail(0.3), tail(3)
+	assert.Greater(t, long, short+20, "short %.1f dB, long %.1f dB", short, long)
+}
+
+func TestConvolutionReverb(t *testing.T) {
+	// A 4096-tap IR spans several partitions; only the first tap is set
+	ir := make([]int16, 4096)
+	ir[0] = math.MaxInt16
+	conv, err := NewConvolutionReverb(48000, ir)
+	require.NoError(t, err)
+	defer conv.Close()
+
+	input := make([]int16, 48000/2)
+	for i := range input {
+		input[i] = int16(10000 * math.Sin(2*math.Pi*440*float64(i)/48000))
+	}
+	var output []int16
+	for start := 0; start < len(input); start += 480 {
+		output = append(output, conv.Process(input[start:start+480])...)
+	}
+	require.Len(t, output, len(input))
+
+	latency := conv.Latency()
+	for i := latency; i < len(output); i++ {
+		require.InDelta(t, input[i-latency], output[i], 1, "sample %d", i)
+	}
+
+	_, err = NewConvolutionReverb(48000, nil)
+	assert.Error(t, err)
+}
+
 func TestDelay(t *testing.T) {
 	delay, err := NewDelay(48000, 250, 0.4)
 	require.NoError(t, err)
@@ -45,6 +109,153 @@ func TestDelay(t *testing.T) {
 	assert.Len(t, output, len(input))
 }
 
+func TestDelayScheduleFeedback(t *testing.T) {
+	// 100ms block with an impulse at the start and 10ms echoes
+	input := make([]int16, 4800)
+	input[0] = 20000
+
+	scheduled, err := NewDelay(48000, 10, 0.2)
+	require.NoError(t, err)
+	defer scheduled.Close()
+	scheduled.ScheduleFeedback(2400, 0.9)
+	automated := scheduled.Process(input)
+	require.Len(t, automated, len(input))
+
+	upfront, err := NewDelay(48000, 10, 0.2)
+	require.NoError(t, err)
+	defer upfront.Close()
+	upfront.SetFeedback(0.9)
+	immediate := upfront.Process(input)
+
+	// The first half differs because only the upfront change affected it
+	differs := 0
+	for i := 0; i < 2400; i++ {
+		if immediate[i] != automated[i] {
+			differs++
+		}
+	}
+	assert.Greater(t, differs, 0)
+
+	// The schedule is consumed by Process
+	assert.Empty(t, scheduled.feedbackEvents)
+	assert.Equal(t, float32(0.9), scheduled.feedback)
+}
+
+func TestReverbScheduleWetLevel(t *testing.T) {
+	reverb, err := NewReverb(48000, 0.7, 0.0)
+	require.NoError(t, err)
+	defer reverb.Close()
+
+	input := make([]int16, 4800)
+	for i := range input {
+		input[i] = int16(i * 5)
+	}
+	reverb.ScheduleWetLevel(100, 0.5)
+	reverb.ScheduleWetLevel(4000, 0.2)
+	output := reverb.Process(input)
+	assert.Len(t, output, len(input))
+}
+
+func TestReverbProcessWetOnly(t *testing.T) {
+	full, err := NewReverb(48000, 0.7, 0.3)
+	require.NoError(t, err)
+	defer full.Close()
+	wet, err := NewReverb(48000, 0.7, 0.3)
+	require.NoError(t, err)
+	defer wet.Close()
+
+	// A short burst followed by silence, so the second half is pure tail
+	input := make([]int16, 9600)
+	copy(input, Sine(48000, 440, 0.3, 480))
+	fullOut := full.Process(input)
+	wetOut := wet.ProcessWetOnly(input)
+	require.Len(t, wetOut, len(input))
+
+	// Where the input is silent Process is the tail at the wet level, while
+	// ProcessWetOnly returns the tail unscaled
+	assert.Greater(t, rmsDbfs(wetOut[4800:]), float32(-90))
+	for i := 4800; i < len(input); i++ {
+		require.InDelta(t, 0.3*float64(wetOut[i]), fullOut[i], 2, "sample %d", i)
+	}
+}
+
+func TestProcessWetOnly(t *testing.T) {
+	type wetOnlyEffect interface {
+		SetMix(float32)
+		Process([]int16) []int16
+		ProcessWetOnly([]int16) []int16
+		Close() error
+	}
+	effects := map[string]func() (wetOnlyEffect, error){
+		"delay":   func() (wetOnlyEffect, error) { return NewDelay(48000, 5, 0.3) },
+		"chorus":  func() (wetOnlyEffect, error) { return NewChorus(48000, 0.5, 1.5) },
+		"flanger": func() (wetOnlyEffect, error) { return NewFlanger(48000, 0.5, 0.5) },
+	}
+
+	input := Sine(48000, 440, 0.3, 4800)
+	input[0] = 20000
+	for name, create := range effects {
+		full, err := create()
+		require.NoError(t, err)
+		wet, err := create()
+		require.NoError(t, err)
+		full.SetMix(0.5)
+		wet.SetMix(0.5)
+
+		// Process blends the dry input with exactly what ProcessWetOnly
+		// returns, which holds none of the dry signal
+		fullOut := full.Process(input)
+		wetOut := wet.ProcessWetOnly(input)
+		require.Len(t, wetOut, len(input), name)
+		assert.Zero(t, wetOut[0], name)
+		assert.Greater(t, rmsDbfs(wetOut), float32(-40), name)
+		for i, s := range input {
+			require.InDelta(t, 0.5*float64(s)+0.5*float64(wetOut[i]), fullOut[i], 2, "%s sample %d", name, i)
+		}
+		full.Close()
+		wet.Close()
+	}
+}
+
+func TestDelayTempoSync(t *testing.T) {
+	delay, err := NewDelay(48000, 250, 0.4)
+	require.NoError(t, err)
+	defer delay.Close()
+	assert.Equal(t, float32(250), delay.DelayTime())
+
+	delay.SetDelayTempo(120, NoteQuarter)
+	assert.InDelta(t, 500, delay.DelayTime(), 0.001)
+
+	delay.SetDelayTempo(120, NoteDottedEighth)
+	assert.InDelta(t, 375, delay.DelayTime(), 0.001)
+
+	delay.SetDelayTempo(120, NoteEighthTriplet)
+	assert.InDelta(t, 166.667, delay.DelayTime(), 0.001)
+}
+
+func TestDelayPingPong(t *testing.T) {
+	delay, err := NewDelay(48000, 100, 0.5)
+	require.NoError(t, err)
+	defer delay.Close()
+	delay.SetStereoSpread(1.0)
+
+	// Left-only impulse followed by silence, 150ms of stereo audio
+	input := make([]int16, 7200*2)
+	input[0] = 20000
+	output := delay.ProcessStereoPingPong(input)
+	require.Len(t, output, len(input))
+
+	// The first echo arrives at 100ms on the right channel
+	var left, right float64
+	for i := 4800 - 48; i < 4800+480; i++ {
+		left += math.Abs(float64(output[2*i]))
+		right += math.Abs(float64(output[2*i+1]))
+	}
+	assert.Greater(t, right, left)
+
+	assert.Nil(t, delay.ProcessStereoPingPong(input[:3]))
+}
+
 func TestPitchShifter(t *testing.T) {
 	shifter, err := NewPitchShifter(48000, 5.0)
 	require.NoError(t, err)
@@ -60,7 +271,53 @@ func TestPitchShifter(t *testing.T) {
 		input[i] = int16(i * 30)
 	}
 	output := shifter.Process(input)
-	assert.Len(t, output, len(input))
+	assert.Len(t, output, len(input)/pitchBlockSize*pitchBlockSize)
+}
+
+func TestPitchShifterIrregularBlocks(t *testing.T) {
+	shifter, err := NewPitchShifter(48000, 7.0)
+	require.NoError(t, err)
+	defer shifter.Close()
+
+	total, emitted := 0, 0
+	for i, size := range []int{1, 100, 333, 480, 7, 1024, 250, 91} {
+		input := make([]int16, size)
+		for j := range input {
+			input[j] = int16(8000 * math.Sin(2*math.Pi*440*float64(total+j)/48000))
+		}
+		total += size
+		emitted += len(shifter.Process(input))
+		// Never more than one block behind the input
+		assert.LessOrEqual(t, emitted, total, "block %d", i)
+		assert.Greater(t, emitted, total-pitchBlockSize, "block %d", i)
+	}
+
+	emitted += len(shifter.Flush())
+	assert.Equal(t, total+shifter.Latency(), emitted)
+}
+
+func TestWarpProcessor(t *testing.T) {
+	const rate = 48000
+	warp, err := NewWarpProcessor(rate)
+	require.NoError(t, err)
+	defer warp.Close()
+
+	warp.SetTimeRatio(2.0)
+	warp.SetPitchSemitones(12)
+
+	input := make([]int16, rate)
+	for i := range input {
+		input[i] = int16(10000 * math.Sin(2*math.Pi*440*float64(i)/rate))
+	}
+	output := warp.ProcessAll(input)
+	assert.InDelta(t, 2*len(input), len(output), 0.05*float64(2*len(input)))
+
+	// The octave-up tone dominates the original pitch
+	samples := make([]float64, len(output))
+	for i, v := range output {
+		samples[i] = float64(v)
+	}
+	assert.Greater(t, goertzelPower(samples, rate, 880), 10*goertzelPower(samples, rate, 440))
 }
 
 func TestChorus(t *testing.T) {
@@ -101,6 +358,78 @@ func TestFlanger(t *testing.T) {
 	assert.Len(t, output, len(input))
 }
 
+func TestEffectsDryMix(t *testing.T) {
+	delay, err := NewDelay(48000, 5, 0.5)
+	require.NoError(t, err)
+	defer delay.Close()
+	chorus, err := NewChorus(48000, 0.5, 1.5)
+	require.NoError(t, err)
+	defer chorus.Close()
+	flanger, err := NewFlanger(48000, 0.5, 0.5)
+	require.NoError(t, err)
+	defer flanger.Close()
+	input := make([]int16, 4*pitchBlockSize)
+	for i := range input {
+		input[i] = int16(10000 * math.Sin(2*math.Pi*440*float64(i)/48000))
+	}
+
+	effects := map[string]interface {
+		SetMix(float32)
+		Process([]int16) []int16
+	}{
+		"delay":   delay,
+		"chorus":  chorus,
+		"flanger": flanger,
+	}
+	for name, effect := range effects {
+		effect.SetMix(0)
+		for block := 0; block < 3; block++ {
+			assert.Equal(t, input, effect.Process(input), name)
+		}
+	}
+
+	// The pitch shifter's dry path is delayed to line up with its output
+	shifter, err := NewPitchShifter(48000, 5)
+	require.NoError(t, err)
+	defer shifter.Close()
+	shifter.SetMix(0)
+	var output, want []int16
+	want = make([]int16, shifter.Latency())
+	for block := 0; block < 3; block++ {
+		output = append(output, shifter.Process(input)...)
+		want = append(want, input...)
+	}
+	assert.Equal(t, want[:len(output)], output)
+
+	// so an unshifted half mix keeps the level instead of combing
+	unison, err := NewPitchShifter(48000, 0)
+	require.NoError(t, err)
+	defer unison.Close()
+	unison.SetMix(0.5)
+	unison.Process(input)
+	assert.InDelta(t, rmsDbfs(input), rmsDbfs(unison.Process(input)), 1)
+
+	// A blend of loud wet and dry samples of opposite sign stays between
+	// them rather than wrapping around
+	square := make([]int16, 4*pitchBlockSize)
+	for i := range square {
+		square[i] = 20000
+		if i/50%2 == 1 {
+			square[i] = -20000
+		}
+	}
+	fifth, err := NewPitchShifter(48000, 7)
+	require.NoError(t, err)
+	defer fifth.Close()
+	fifth.SetMix(0.5)
+	for block := 0; block < 3; block++ {
+		for _, s := range fifth.Process(square) {
+			require.LessOrEqual(t, s, int16(20000))
+			require.GreaterOrEqual(t, s, int16(-20000))
+		}
+	}
+}
+
 func TestTimeStretcher(t *testing.T) {
 	stretcher, err := NewTimeStretcher(48000, 1.5)
 	require.NoError(t, err)
@@ -119,6 +448,27 @@ func TestTimeStretcher(t *testing.T) {
 	assert.Greater(t, len(output), 0)
 }
 
+func TestTimeStretcherFlush(t *testing.T) {
+	const ratio = 1.5
+	stretcher, err := NewTimeStretcher(48000, ratio)
+	require.NoError(t, err)
+	defer stretcher.Close()
+
+	input := make([]int16, 480)
+	total, emitted := 0, 0
+	for block := 0; block < 50; block++ {
+		for i := range input {
+			input[i] = int16(8000 * math.Sin(2*math.Pi*300*float64(total+i)/48000))
+		}
+		total += len(input)
+		emitted += len(stretcher.Process(input))
+	}
+	emitted += len(stretcher.Flush())
+
+	want := ratio * float64(total)
+	assert.InDelta(t, want, emitted, 0.02*want)
+}
+
 func TestWatermarkEmbedder(t *testing.T) {
 	embedder, err := NewWatermarkEmbedder(48000, 0.1)
 	require.NoError(t, err)
@@ -131,10 +481,76 @@ func TestWatermarkEmbedder(t *testing.T) {
 		input[i] = int16(i * 5)
 	}
 	payload := []byte("test-watermark")
-	output := embedder.Embed(input, payload)
+	output, err := embedder.Embed(input, payload)
+	require.NoError(t, err)
 	assert.Len(t, output, len(input))
 }
 
+func TestWatermarkEmbedderCapacity(t *testing.T) {
+	embedder, err := NewWatermarkEmbedder(48000, 0.1)
+	require.NoError(t, err)
+	defer embedder.Close()
+
+	input := make([]int16, 4800)
+	capacity := embedder.Capacity(len(input))
+	assert.Greater(t, capacity, 0)
+
+	// Payload that exactly fits succeeds, one byte more fails
+	_, err = embedder.Embed(input, make([]byte, capacity))
+	assert.NoError(t, err)
+	_, err = embedder.Embed(input, make([]byte, capacity+1))
+	assert.Error(t, err)
+
+	// Streaming spreads a larger payload across blocks
+	payload := make([]byte, capacity*3)
+	offset := 0
+	for blocks := 0; offset < len(payload); blocks++ {
+		require.Less(t, blocks, 3)
+		var out []int16
+		out, offset, err = embedder.EmbedStreaming(input, payload, offset)
+		require.NoError(t, err)
+		assert.Len(t, out, len(input))
+	}
+	assert.Equal(t, len(payload), offset)
+}
+
+func TestWatermarkEmbedderValidation(t *testing.T) {
+	_, err := NewWatermarkEmbedder(48000, 1.5)
+	assert.ErrorIs(t, err, ErrInvalidRange)
+	_, err = NewWatermarkEmbedder(48000, -0.1)
+	assert.ErrorIs(t, err, ErrInvalidRange)
+
+	embedder, err := NewWatermarkEmbedder(48000, 0.1)
+	require.NoError(t, err)
+	defer embedder.Close()
+
+	// The native maximum follows the strength
+	weak := embedder.MaxPayloadLen()
+	assert.NoError(t, embedder.SetStrength(0.8))
+	assert.Greater(t, embedder.MaxPayloadLen(), weak)
+	assert.ErrorIs(t, embedder.SetStrength(2), ErrInvalidRange)
+
+	// Payloads over the maximum are rejected even when the audio has room
+	input := make([]int16, 480000)
+	require.Greater(t, embedder.Capacity(len(input)), 64)
+	require.NoError(t, embedder.SetMaxPayloadLen(64))
+	assert.Equal(t, 64, embedder.MaxPayloadLen())
+	_, err = embedder.Embed(input, make([]byte, 64))
+	assert.NoError(t, err)
+	_, err = embedder.Embed(input, make([]byte, 65))
+	assert.ErrorIs(t, err, ErrInvalidRange)
+	_, _, err = embedder.EmbedStreaming(input, make([]byte, 65), 0)
+	assert.ErrorIs(t, err, ErrInvalidRange)
+	assert.ErrorIs(t, embedder.SetMaxPayloadLen(0), ErrInvalidRange)
+
+	// A limit set with SetMaxPayloadLen survives strength changes
+	require.NoError(t, embedder.SetStrength(0.1))
+	assert.Equal(t, 64, embedder.MaxPayloadLen())
+
+	embedder.Close()
+	assert.Error(t, embedder.SetStrength(0.5))
+}
+
 func TestWatermarkDetector(t *testing.T) {
 	detector, err := NewWatermarkDetector(48000)
 	require.NoError(t, err)
@@ -149,3 +565,299 @@ func TestWatermarkDetector(t *testing.T) {
 	payload, confidence := detector.Detect(input)
 	t.Logf("Watermark payload len: %d, confidence: %.2f", len(payload), confidence)
 }
+
+func TestWatermarkDetectIntoLargePayload(t *testing.T) {
+	embedder, err := NewWatermarkEmbedder(48000, 0.5)
+	require.NoError(t, err)
+	defer embedder.Close()
+
+	detector, err := NewWatermarkDetector(48000)
+	require.NoError(t, err)
+	defer detector.Close()
+	assert.Greater(t, detector.MaxPayloadLen(), 256)
+
+	// 10 seconds of audio carrying a 300-byte payload
+	input := make([]int16, 480000)
+	for i := range input {
+		input[i] = int16((i % 200) * 50)
+	}
+	payload := make([]byte, 300)
+	for i := range payload {
+		payload[i] = byte(i)
+	}
+	marked, err := embedder.Embed(input, payload)
+	require.NoError(t, err)
+	require.Len(t, marked, len(input))
+
+	buf := make([]byte, 1024)
+	n, confidence, err := detector.DetectInto(marked, buf)
+	require.NoError(t, err)
+	assert.Equal(t, len(payload), n)
+	assert.Equal(t, payload, buf[:n])
+	t.Logf("Watermark confidence: %.2f", confidence)
+
+	// A buffer that is too small reports the required size
+	n, _, err = detector.DetectInto(marked, make([]byte, 16))
+	assert.Error(t, err)
+	assert.Equal(t, len(payload), n)
+}
+
+func TestWatermarkString(t *testing.T) {
+	embedder, err := NewWatermarkEmbedder(48000, 0.3)
+	require.NoError(t, err)
+	defer embedder.Close()
+	detector, err := NewWatermarkDetector(48000)
+	require.NoError(t, err)
+	defer detector.Close()
+
+	const id = "user-42/session-abc"
+	input := Sine(48000, 440, 0.25, 96000)
+	marked := embedder.EmbedString(input, id)
+	require.Len(t, marked, len(input))
+
+	// Mild noise, about -40dBFS
+	noise := WhiteNoise(len(marked), 0.017, 1)
+	for i := range marked {
+		marked[i] = clampInt16(float64(marked[i]) + float64(noise[i]))
+	}
+	s, confidence := detector.DetectString(marked)
+	assert.Equal(t, id, s)
+	assert.Greater(t, confidence, float32(0))
+
+	// The vote outweighs errors confined to one copy; corrupting two copies
+	// of the same bit fails the CRC
+	payload := encodeWatermarkString(id)
+	size := len(payload) / watermarkCopies
+	payload[3] ^= 0x10
+	payload[size+7] ^= 0x81
+	s, ok := decodeWatermarkString(payload)
+	assert.True(t, ok)
+	assert.Equal(t, id, s)
+	payload[2*size+3] ^= 0x10
+	_, ok = decodeWatermarkString(payload)
+	assert.False(t, ok)
+
+	// Too little audio to carry the string
+	assert.Nil(t, embedder.EmbedString(input[:1000], id))
+}
+
+func TestWatermarkRepeating(t *testing.T) {
+	embedder, err := NewWatermarkEmbedder(48000, 0.3)
+	require.NoError(t, err)
+	defer embedder.Close()
+	detector, err := NewWatermarkDetector(48000)
+	require.NoError(t, err)
+	defer detector.Close()
+
+	const period = 24000
+	payload := []byte("cropped")
+	input := Sine(48000, 440, 0.25, 4*48000)
+	marked := embedder.EmbedRepeating(input, payload, period)
+	require.Len(t, marked, len(input))
+
+	// An excerpt starting mid-period, long enough to hold one whole copy
+	excerpt := marked[37123 : 37123+30000]
+	found, confidence := detector.DetectRepeating(excerpt, period)
+	assert.Equal(t, payload, found)
+	assert.Greater(t, confidence, float32(0.5))
+
+	// The detector locks on to a copy that starts partway into its first
+	// symbol, which is what lets DetectRepeating step a symbol at a time
+	found, _ = detector.Detect(marked[period-7 : 2*period])
+	assert.Equal(t, payload, found)
+
+	// A payload that does not fit in one period
+	assert.Nil(t, embedder.EmbedRepeating(input, make([]byte, embedder.Capacity(period)+1), period))
+}
+
+func TestStereoWidth(t *testing.T) {
+	width, err := NewStereoWidth(48000)
+	require.NoError(t, err)
+	require.NotNil(t, width)
+	defer width.Close()
+
+	// Uncorrelated left and right channels
+	input := make([]int16, 960)
+	for i := 0; i < len(input); i += 2 {
+		input[i] = int16(i * 20)
+		input[i+1] = int16(-i * 10)
+	}
+
+	// Width 0 collapses to mono
+	width.SetWidth(0)
+	output := width.Process(input)
+	require.Len(t, output, len(input))
+	for i := 0; i < len(output); i += 2 {
+		assert.Equal(t, output[i], output[i+1])
+	}
+
+	// Odd-length input is rejected
+	assert.Nil(t, width.Process(input[:3]))
+}
+
+func TestBitCrusher(t *testing.T) {
+	crusher, err := NewBitCrusher(48000, 16, 1)
+	require.NoError(t, err)
+	require.NotNil(t, crusher)
+	defer crusher.Close()
+
+	crusher.SetBits(4)
+	crusher.SetDownsample(1)
+
+	// Full-range ramp
+	input := make([]int16, 4096)
+	for i := range input {
+		input[i] = int16(i*16 - 32768)
+	}
+	output := crusher.Process(input)
+	require.Len(t, output, len(input))
+
+	levels := make(map[int16]bool)
+	for _, s := range output {
+		levels[s] = true
+	}
+	assert.LessOrEqual(t, len(levels), 16)
+}
+
+func TestSaturator(t *testing.T) {
+	const rate = 48000
+	input := make([]int16, rate/2)
+	samples := make([]float64, len(input))
+	for i := range input {
+		input[i] = int16(20000 * math.Sin(2*math.Pi*1000*float64(i)/rate))
+		samples[i] = float64(input[i])
+	}
+	inputH2 := goertzelPower(samples, rate, 2000)
+	inputH3 := goertzelPower(samples, rate, 3000)
+
+	for _, curve := range []SaturationCurve{SaturationSoft, SaturationHard, SaturationTube, SaturationTanh} {
+		sat, err := NewSaturator(rate, 4, curve)
+		require.NoError(t, err)
+
+		output := sat.Process(input)
+		require.Len(t, output, len(input))
+		for i, v := range output {
+			samples[i] = float64(v)
+		}
+		fundamental := goertzelPower(samples, rate, 1000)
+		harmonics := goertzelPower(samples, rate, 2000) + goertzelPower(samples, rate, 3000)
+		assert.Greater(t, harmonics, 1000*(inputH2+inputH3), "curve %d", curve)
+		assert.Greater(t, harmonics/fundamental, 1e-3, "curve %d", curve)
+		sat.Close()
+	}
+}
+
+func TestProcessFloat(t *testing.T) {
+	type floatEffect interface {
+		Process([]int16) []int16
+		ProcessFloat([]float32) []float32
+		Close() error
+	}
+	constructors := map[string]func() (floatEffect, error){
+		"reverb":     func() (floatEffect, error) { return NewReverb(48000, 0.5, 0.3) },
+		"delay":      func() (floatEffect, error) { return NewDelay(48000, 5, 0.4) },
+		"chorus":     func() (floatEffect, error) { return NewChorus(48000, 0.5, 1.5) },
+		"flanger":    func() (floatEffect, error) { return NewFlanger(48000, 0.5, 0.5) },
+		"compressor": func() (floatEffect, error) { return NewCompressor(48000, -20, 4, 10, 100) },
+		"equalizer":  func() (floatEffect, error) { return NewEqualizer(48000, 3) },
+	}
+
+	input := make([]int16, 960)
+	for i := range input {
+		input[i] = int16(12000 * math.Sin(2*math.Pi*440*float64(i)/48000))
+	}
+	floatInput := int16ToFloat32(input)
+
+	for name, create := range constructors {
+		intPath, err := create()
+		require.NoError(t, err, name)
+		floatPath, err := create()
+		require.NoError(t, err, name)
+
+		want := intPath.Process(input)
+		got := floatPath.ProcessFloat(floatInput)
+		require.Len(t, got, len(want), name)
+		for i := range want {
+			require.InDelta(t, float32(want[i])/32768, got[i], 1e-3, "%s sample %d", name, i)
+		}
+		intPath.Close()
+		floatPath.Close()
+	}
+}
+
+func TestRingModulator(t *testing.T) {
+	const sampleRate = 16000
+	ring, err := NewRingModulator(sampleRate, 100)
+	require.NoError(t, err)
+	defer ring.Close()
+
+	input := make([]int16, sampleRate)
+	for i := range input {
+		input[i] = int16(10000 * math.Sin(2*math.Pi*1000*float64(i)/sampleRate))
+	}
+	output := ring.Process(input)
+	require.Len(t, output, len(input))
+
+	// 1kHz against a 100Hz carrier gives 900Hz and 1100Hz and no 1kHz
+	tone := harmonicLevel(input, sampleRate, 1000)
+	assert.InDelta(t, tone/2, harmonicLevel(output, sampleRate, 900), tone*0.05)
+	assert.InDelta(t, tone/2, harmonicLevel(output, sampleRate, 1100), tone*0.05)
+	assert.Less(t, harmonicLevel(output, sampleRate, 1000), tone*0.05)
+
+	_, err = NewRingModulator(sampleRate, -1)
+	assert.ErrorIs(t, err, ErrInvalidRange)
+}
+
+func TestAutoWah(t *testing.T) {
+	const sampleRate = 16000
+	wah, err := NewAutoWah(sampleRate, 0.8, 300, 3000, 4)
+	require.NoError(t, err)
+	defer wah.Close()
+
+	// Quiet noise, then a loud burst of the same noise
+	rng := rand.New(rand.NewSource(1))
+	input := make([]int16, sampleRate)
+	for i := range input {
+		amp := 300
+		if i >= sampleRate/2 {
+			amp = 15000
+		}
+		input[i] = int16((rng.Float64()*2 - 1) * float64(amp))
+	}
+	output := wah.Process(input)
+	require.Len(t, output, len(input))
+	assert.Greater(t, wah.CenterHz(), float32(2500))
+
+	// Spectral centroid of a 100ms window, from 50Hz bins up to 6kHz
+	centroid := func(window []int16) float64 {
+		var weighted, total float64
+		for hz := 50.0; hz <= 6000; hz += 50 {
+			level := harmonicLevel(window, sampleRate, hz)
+			weighted += hz * level
+			total += level
+		}
+		return weighted / total
+	}
+	quiet := centroid(output[sampleRate/4 : sampleRate/4+1600])
+	loud := centroid(output[sampleRate*3/4 : sampleRate*3/4+1600])
+	assert.Greater(t, loud, quiet+500, "centroid %.0f Hz quiet, %.0f Hz loud", quiet, loud)
+}
+
+func TestSubHarmonic(t *testing.T) {
+	const sampleRate = 16000
+	sub, err := NewSubHarmonic(sampleRate)
+	require.NoError(t, err)
+	defer sub.Close()
+
+	input := make([]int16, 2*sampleRate)
+	for i := range input {
+		input[i] = int16(8000 * math.Sin(2*math.Pi*200*float64(i)/sampleRate))
+	}
+	output := sub.Process(input)
+	require.Len(t, output, len(input))
+
+	// Skip the first half second while the tracking settles
+	in, out := input[sampleRate/2:], output[sampleRate/2:]
+	assert.Greater(t, harmonicLevel(out, sampleRate, 100), 10*harmonicLevel(in, sampleRate, 100))
+	assert.Greater(t, harmonicLevel(out, sampleRate, 100), harmonicLevel(out, sampleRate, 200)/10)
+}
@@ -0,0 +1,114 @@
+package sonickit
+
+import (
+	"math"
+)
+
+// EnvelopeScale selects the units returned by EnvelopeFollower.
+type EnvelopeScale int
+
+const (
+	// EnvelopeLinear reports the envelope as linear amplitude (0.0-1.0 of
+	// full scale).
+	EnvelopeLinear EnvelopeScale = 0
+	// EnvelopeDb reports the envelope in dBFS, floored at -100.
+	EnvelopeDb EnvelopeScale = 1
+)
+
+// EnvelopeFollower tracks the amplitude envelope of a signal with separate
+// attack and release time constants, for side-chain and dynamics use.
+type EnvelopeFollower struct {
+	sampleRate  int
+	attackCoef  float64
+	releaseCoef float64
+	scale       EnvelopeScale
+	env         float64
+}
+
+// NewEnvelopeFollower creates a new envelope follower.
+//
+// Parameters:
+//   - sampleRate: Audio sample rate in Hz
+//   - attackMs: Time constant for rising levels in milliseconds
+//   - releaseMs: Time constant for falling levels in milliseconds
+func NewEnvelopeFollower(sampleRate int, attackMs, releaseMs float32) (*EnvelopeFollower, error) {
+	if err := validate("envelope follower",
+		checkPositiveRate(sampleRate),
+		checkRange("attack", float64(attackMs), 0, math.MaxFloat32),
+		checkRange("release", float64(releaseMs), 0, math.MaxFloat32),
+	); err != nil {
+		return nil, err
+	}
+	return &EnvelopeFollower{
+		sampleRate:  sampleRate,
+		attackCoef:  timeConstantCoef(sampleRate, attackMs),
+		releaseCoef: timeConstantCoef(sampleRate, releaseMs),
+	}, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (e *EnvelopeFollower) SampleRate() int {
+	return e.sampleRate
+}
+
+// timeConstantCoef returns the one-pole smoothing coefficient that reaches
+// 1-1/e of a step after ms milliseconds.
+func timeConstantCoef(sampleRate int, ms float32) float64 {
+	if ms <= 0 {
+		return 0
+	}
+	return math.Exp(-1 / (float64(ms) * 0.001 * float64(sampleRate)))
+}
+
+// SetScale selects linear or dB output.
+func (e *EnvelopeFollower) SetScale(scale EnvelopeScale) {
+	e.scale = scale
+}
+
+// next advances the envelope by one sample and returns it linearly.
+func (e *EnvelopeFollower) next(sample int16) float64 {
+	x := math.Abs(float64(sample)) / 32768
+	coef := e.releaseCoef
+	if x > e.env {
+		coef = e.attackCoef
+	}
+	e.env = coef*e.env + (1-coef)*x
+	return e.env
+}
+
+// Process returns the envelope for each input sample.
+func (e *EnvelopeFollower) Process(input []int16) []float32 {
+	if len(input) == 0 {
+		return nil
+	}
+	output := make([]float32, len(input))
+	for i, s := range input {
+		output[i] = e.convert(e.next(s))
+	}
+	return output
+}
+
+// Current returns the envelope after the most recently processed sample.
+func (e *EnvelopeFollower) Current() float32 {
+	return e.convert(e.env)
+}
+
+// Reset returns the envelope to zero.
+func (e *EnvelopeFollower) Reset() {
+	e.env = 0
+}
+
+func (e *EnvelopeFollower) convert(v float64) float32 {
+	if e.scale == EnvelopeDb {
+		return linearToDb(v)
+	}
+	return float32(v)
+}
+
+// linearToDb converts a linear amplitude to dB, floored at -100.
+func linearToDb(v float64) float32 {
+	if v <= 1e-5 {
+		return -100
+	}
+	return float32(20 * math.Log10(v))
+}
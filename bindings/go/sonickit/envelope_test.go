@@ -0,0 +1,36 @@
+package sonickit
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelopeFollower(t *testing.T) {
+	env, err := NewEnvelopeFollower(48000, 10, 100)
+	require.NoError(t, err)
+	require.NotNil(t, env)
+
+	// Step from silence to half scale
+	input := make([]int16, 4800)
+	for i := 2400; i < len(input); i++ {
+		input[i] = 16384
+	}
+	output := env.Process(input)
+	require.Len(t, output, len(input))
+	assert.Equal(t, float32(0), output[2399])
+
+	// After one attack time constant (10ms = 480 samples) the envelope has
+	// covered 1-1/e of the step
+	expected := 0.5 * (1 - 1/math.E)
+	assert.InDelta(t, expected, output[2400+479], 0.01)
+	assert.InDelta(t, 0.5, env.Current(), 0.01)
+
+	// dB output
+	env.SetScale(EnvelopeDb)
+	assert.InDelta(t, -6.02, env.Current(), 0.2)
+	env.Reset()
+	assert.Equal(t, float32(-100), env.Current())
+}
@@ -0,0 +1,163 @@
+package sonickit
+
+/*
+#include "voice/error.h"
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Errors returned (possibly wrapped) by constructors. Test for them with
+// errors.Is.
+var (
+	// ErrInvalidSampleRate reports a sample rate the library cannot run at.
+	ErrInvalidSampleRate = errors.New("invalid sample rate")
+	// ErrInvalidFrameSize reports a frame size that is not positive.
+	ErrInvalidFrameSize = errors.New("invalid frame size")
+	// ErrInvalidChannelCount reports a channel count that is not positive.
+	ErrInvalidChannelCount = errors.New("invalid channel count")
+	// ErrInvalidRange reports a parameter outside its allowed range.
+	ErrInvalidRange = errors.New("value out of range")
+	// ErrInvalidParam reports a parameter the native library rejected.
+	ErrInvalidParam = errors.New("invalid parameter")
+	// ErrOutOfMemory reports a failed native allocation.
+	ErrOutOfMemory = errors.New("out of memory")
+	// ErrNotSupported reports a feature missing from the native build.
+	ErrNotSupported = errors.New("not supported")
+)
+
+// supportedSampleRates lists the rates the native processors accept.
+var supportedSampleRates = []int{
+	8000, 11025, 12000, 16000, 22050, 24000, 32000, 44100, 48000, 88200, 96000,
+}
+
+// checkSampleRate returns an error wrapping ErrInvalidSampleRate if
+// sampleRate is not one of the supported rates.
+func checkSampleRate(sampleRate int) error {
+	for _, r := range supportedSampleRates {
+		if r == sampleRate {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %d Hz", ErrInvalidSampleRate, sampleRate)
+}
+
+// checkPositiveRate is checkSampleRate for processors, such as the
+// resampler, that accept arbitrary rates.
+func checkPositiveRate(sampleRate int) error {
+	if sampleRate <= 0 {
+		return fmt.Errorf("%w: %d Hz", ErrInvalidSampleRate, sampleRate)
+	}
+	return nil
+}
+
+// checkFrameSize returns an error wrapping ErrInvalidFrameSize if frameSize
+// is not positive.
+func checkFrameSize(frameSize int) error {
+	if frameSize <= 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidFrameSize, frameSize)
+	}
+	return nil
+}
+
+// vadFrameMs lists the frame durations the VAD accepts.
+var vadFrameMs = []int{10, 20, 30}
+
+// checkVadFrameSize returns an error wrapping ErrInvalidFrameSize if
+// frameSize is not one of the frame durations the VAD accepts at
+// sampleRate, naming the nearest size that is.
+func checkVadFrameSize(sampleRate, frameSize int) error {
+	best, bestDist := 0, math.MaxInt
+	for _, ms := range vadFrameMs {
+		size := RecommendedFrameSize(sampleRate, ms)
+		if size == frameSize {
+			return nil
+		}
+		if dist := max(size-frameSize, frameSize-size); dist < bestDist {
+			best, bestDist = size, dist
+		}
+	}
+	return fmt.Errorf("%w: %d samples is not 10, 20 or 30ms at %d Hz (nearest is %d, see RecommendedFrameSize)",
+		ErrInvalidFrameSize, frameSize, sampleRate, best)
+}
+
+// checkChannels returns an error wrapping ErrInvalidChannelCount if channels
+// is not positive.
+func checkChannels(channels int) error {
+	if channels <= 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidChannelCount, channels)
+	}
+	return nil
+}
+
+// checkRange returns an error wrapping ErrInvalidRange if value lies outside
+// [min, max].
+func checkRange(name string, value, min, max float64) error {
+	if value < min || value > max || math.IsNaN(value) {
+		return fmt.Errorf("%w: %s %g not in [%g, %g]", ErrInvalidRange, name, value, min, max)
+	}
+	return nil
+}
+
+// checkPowerOfTwo returns an error wrapping ErrInvalidRange if value is not
+// a power of two.
+func checkPowerOfTwo(name string, value int) error {
+	if value <= 0 || value&(value-1) != 0 {
+		return fmt.Errorf("%w: %s %d is not a power of two", ErrInvalidRange, name, value)
+	}
+	return nil
+}
+
+// checkUnit is checkRange for parameters in 0..1.
+func checkUnit(name string, value float32) error {
+	return checkRange(name, float64(value), 0, 1)
+}
+
+// validate returns the first failed check, prefixed with the constructor
+// it applies to.
+func validate(what string, checks ...error) error {
+	for _, err := range checks {
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", what, err)
+		}
+	}
+	return nil
+}
+
+// lastError returns the error recorded by the most recent failing native
+// call and clears it, or nil if none was recorded. The native error state is
+// process-wide, so concurrent failures may report each other's cause.
+func lastError() error {
+	code := C.voice_get_last_error()
+	C.voice_clear_error()
+	switch code {
+	case C.VOICE_OK:
+		return nil
+	case C.VOICE_ERROR_INVALID_PARAM:
+		return ErrInvalidParam
+	case C.VOICE_ERROR_OUT_OF_MEMORY:
+		return ErrOutOfMemory
+	case C.VOICE_ERROR_NOT_SUPPORTED:
+		return ErrNotSupported
+	}
+	return errors.New(C.GoString(C.voice_error_string(code)))
+}
+
+// clearError discards any error left by an earlier native call. Constructors
+// call it just before the native create call, so that createError cannot
+// report a stale cause.
+func clearError() {
+	C.voice_clear_error()
+}
+
+// createError builds the error returned when a native constructor yields a
+// nil handle, including the library's reason when it recorded one.
+func createError(what string) error {
+	if err := lastError(); err != nil {
+		return fmt.Errorf("failed to create %s: %w", what, err)
+	}
+	return fmt.Errorf("failed to create %s", what)
+}
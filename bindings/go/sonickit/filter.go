@@ -0,0 +1,301 @@
+package sonickit
+
+import (
+	"errors"
+	"math"
+)
+
+// biquad is a single second-order IIR section in transposed direct form II.
+type biquad struct {
+	b0, b1, b2 float64
+	a1, a2     float64
+	z1, z2     float64
+}
+
+func (q *biquad) process(x float64) float64 {
+	y := q.b0*x + q.z1
+	q.z1 = q.b1*x - q.a1*y + q.z2
+	q.z2 = q.b2*x - q.a2*y
+	return y
+}
+
+// setNotch configures q as an RBJ notch at freq Hz, keeping its state so the
+// frequency can move without a click.
+func (q *biquad) setNotch(sampleRate, freq, quality float64) {
+	w0 := 2 * math.Pi * freq / sampleRate
+	alpha := math.Sin(w0) / (2 * quality)
+	a0 := 1 + alpha
+	q.b0 = 1 / a0
+	q.b1 = -2 * math.Cos(w0) / a0
+	q.b2 = 1 / a0
+	q.a1 = -2 * math.Cos(w0) / a0
+	q.a2 = (1 - alpha) / a0
+}
+
+// goertzelPower returns the power of samples at freq Hz.
+func goertzelPower(samples []float64, sampleRate, freq float64) float64 {
+	coef := 2 * math.Cos(2*math.Pi*freq/sampleRate)
+	var s1, s2 float64
+	for _, x := range samples {
+		s0 := x + coef*s1 - s2
+		s2, s1 = s1, s0
+	}
+	return s1*s1 + s2*s2 - coef*s1*s2
+}
+
+// Filter is a cascade of biquad sections implemented in Go.
+type Filter struct {
+	sampleRate int
+	sections   []biquad
+}
+
+// NewKWeightingFilter creates a filter implementing the ITU-R BS.1770 / EBU
+// R128 K-weighting curve: a high-shelf pre-filter (about +4dB above 1.5kHz)
+// followed by the RLB high-pass (about 38Hz).
+func NewKWeightingFilter(sampleRate int) (*Filter, error) {
+	if err := validate("K-weighting filter", checkPositiveRate(sampleRate)); err != nil {
+		return nil, err
+	}
+	fs := float64(sampleRate)
+
+	// Stage 1: high-shelf pre-filter
+	f0, gain, q := 1681.974450955533, 3.999843853973347, 0.7071752369554196
+	k := math.Tan(math.Pi * f0 / fs)
+	vh := math.Pow(10, gain/20)
+	vb := math.Pow(vh, 0.4996667741545416)
+	a0 := 1 + k/q + k*k
+	shelf := biquad{
+		b0: (vh + vb*k/q + k*k) / a0,
+		b1: 2 * (k*k - vh) / a0,
+		b2: (vh - vb*k/q + k*k) / a0,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/q + k*k) / a0,
+	}
+
+	// Stage 2: RLB high-pass
+	f0, q = 38.13547087602444, 0.5003270373238773
+	k = math.Tan(math.Pi * f0 / fs)
+	a0 = 1 + k/q + k*k
+	highPass := biquad{
+		b0: 1,
+		b1: -2,
+		b2: 1,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/q + k*k) / a0,
+	}
+
+	return &Filter{sampleRate: sampleRate, sections: []biquad{shelf, highPass}}, nil
+}
+
+// NewHighPassFilter creates a second-order Butterworth high-pass filter,
+// for removing rumble, wind noise and DC. The response is 3dB down at the
+// cutoff and falls 12dB per octave below it.
+func NewHighPassFilter(sampleRate int, cutoffHz float32) (*Filter, error) {
+	if err := validate("high-pass filter",
+		checkPositiveRate(sampleRate),
+		checkRange("cutoff", float64(cutoffHz), 1, float64(sampleRate)/2-1),
+	); err != nil {
+		return nil, err
+	}
+	w0 := 2 * math.Pi * float64(cutoffHz) / float64(sampleRate)
+	alpha := math.Sin(w0) / math.Sqrt2 // sin(w0) / 2Q, with the Butterworth Q of 1/√2
+	cos := math.Cos(w0)
+	a0 := 1 + alpha
+	highPass := biquad{
+		b0: (1 + cos) / 2 / a0,
+		b1: -(1 + cos) / a0,
+		b2: (1 + cos) / 2 / a0,
+		a1: -2 * cos / a0,
+		a2: (1 - alpha) / a0,
+	}
+	return &Filter{sampleRate: sampleRate, sections: []biquad{highPass}}, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (f *Filter) SampleRate() int {
+	return f.sampleRate
+}
+
+// processSample runs one sample through every section.
+func (f *Filter) processSample(x float64) float64 {
+	for i := range f.sections {
+		x = f.sections[i].process(x)
+	}
+	return x
+}
+
+// Process filters the audio.
+func (f *Filter) Process(input []int16) []int16 {
+	if len(input) == 0 {
+		return nil
+	}
+	output := make([]int16, len(input))
+	f.ProcessInto(output, input)
+	return output
+}
+
+// ProcessInto filters src, writing the result to dst without allocating. It
+// returns len(src), or 0 if dst is shorter than src.
+func (f *Filter) ProcessInto(dst, src []int16) int {
+	if len(src) == 0 || len(dst) < len(src) {
+		return 0
+	}
+	for i, s := range src {
+		dst[i] = clampInt16(f.processSample(float64(s)))
+	}
+	return len(src)
+}
+
+// Reset clears the filter state.
+func (f *Filter) Reset() {
+	for i := range f.sections {
+		f.sections[i].z1 = 0
+		f.sections[i].z2 = 0
+	}
+}
+
+// Latency returns 0; the filter processes sample by sample.
+func (f *Filter) Latency() int {
+	return 0
+}
+
+// Close is a no-op; Filter holds no native resources. It allows a Filter to
+// be used as a Processor.
+func (f *Filter) Close() error {
+	return nil
+}
+
+const (
+	// humNotchQ sets the notch bandwidth (about 2Hz at 60Hz).
+	humNotchQ = 30
+	// humTrackRange is the fraction of the nominal frequency the tracker may
+	// drift by.
+	humTrackRange = 0.03
+	// humTrackStep is the tracker's search resolution in Hz.
+	humTrackStep = 0.1
+)
+
+// HumFilter removes mains hum by placing notch filters at a base frequency
+// (typically 50 or 60Hz) and its harmonics. The base frequency adaptively
+// tracks slight drift of the mains frequency.
+type HumFilter struct {
+	sampleRate float64
+	nominal    float64
+	base       float64
+	notches    []biquad
+	analysis   []float64 // one second of input used to track the base
+}
+
+// NewHumFilter creates a new hum filter.
+//
+// Parameters:
+//   - sampleRate: Audio sample rate in Hz
+//   - baseHz: Mains frequency in Hz (50 or 60)
+//   - harmonics: Number of harmonics to notch in addition to the fundamental
+func NewHumFilter(sampleRate int, baseHz float32, harmonics int) (*HumFilter, error) {
+	if err := validate("hum filter", checkPositiveRate(sampleRate)); err != nil {
+		return nil, err
+	}
+	if baseHz <= 0 || harmonics < 0 {
+		return nil, errors.New("failed to create hum filter: invalid parameters")
+	}
+	if float64(baseHz) >= float64(sampleRate)/2 {
+		return nil, errors.New("failed to create hum filter: base frequency above Nyquist")
+	}
+	h := &HumFilter{
+		sampleRate: float64(sampleRate),
+		notches:    make([]biquad, harmonics+1),
+		analysis:   make([]float64, 0, sampleRate),
+	}
+	h.SetBaseFrequency(baseHz)
+	return h, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (h *HumFilter) SampleRate() int {
+	return int(h.sampleRate)
+}
+
+// SetBaseFrequency sets the nominal mains frequency in Hz. Tracking resumes
+// from the new frequency.
+func (h *HumFilter) SetBaseFrequency(hz float32) {
+	if hz <= 0 {
+		return
+	}
+	h.nominal = float64(hz)
+	h.setBase(h.nominal)
+}
+
+// BaseFrequency returns the currently tracked mains frequency in Hz.
+func (h *HumFilter) BaseFrequency() float32 {
+	return float32(h.base)
+}
+
+func (h *HumFilter) setBase(hz float64) {
+	h.base = hz
+	nyquist := h.sampleRate / 2
+	for i := range h.notches {
+		freq := hz * float64(i+1)
+		if freq >= nyquist*0.95 {
+			// Out-of-band harmonics become pass-through sections
+			h.notches[i].b0, h.notches[i].b1, h.notches[i].b2 = 1, 0, 0
+			h.notches[i].a1, h.notches[i].a2 = 0, 0
+			continue
+		}
+		h.notches[i].setNotch(h.sampleRate, freq, humNotchQ)
+	}
+}
+
+// track re-estimates the base frequency from the analysis window by
+// searching for the strongest component near the nominal frequency.
+func (h *HumFilter) track() {
+	best, bestPower := h.base, 0.0
+	span := h.nominal * humTrackRange
+	for f := h.nominal - span; f <= h.nominal+span; f += humTrackStep {
+		if p := goertzelPower(h.analysis, h.sampleRate, f); p > bestPower {
+			best, bestPower = f, p
+		}
+	}
+	// Ignore windows without meaningful hum to avoid wandering on silence
+	if bestPower/float64(len(h.analysis)) < 1 {
+		return
+	}
+	h.setBase(0.5*h.base + 0.5*best)
+}
+
+// Process removes hum from the audio.
+func (h *HumFilter) Process(input []int16) []int16 {
+	if len(input) == 0 {
+		return nil
+	}
+	output := make([]int16, len(input))
+	h.ProcessInto(output, input)
+	return output
+}
+
+// ProcessInto removes hum from src, writing the result to dst without
+// allocating. It returns len(src), or 0 if dst is shorter than src.
+func (h *HumFilter) ProcessInto(dst, src []int16) int {
+	if len(src) == 0 || len(dst) < len(src) {
+		return 0
+	}
+	for i, s := range src {
+		x := float64(s)
+		h.analysis = append(h.analysis, x)
+		if len(h.analysis) == cap(h.analysis) {
+			h.track()
+			h.analysis = h.analysis[:0]
+		}
+		for n := range h.notches {
+			x = h.notches[n].process(x)
+		}
+		dst[i] = clampInt16(x)
+	}
+	return len(src)
+}
+
+// Close is a no-op; HumFilter holds no native resources. It allows a
+// HumFilter to be used as a Processor.
+func (h *HumFilter) Close() error {
+	return nil
+}
@@ -0,0 +1,91 @@
+package sonickit
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sineGainDb measures the gain of f at hz, skipping the settling time.
+func sineGainDb(f *Filter, sampleRate int, hz float64) float64 {
+	input := make([]int16, sampleRate)
+	for i := range input {
+		input[i] = int16(10000 * math.Sin(2*math.Pi*hz*float64(i)/float64(sampleRate)))
+	}
+	output := f.Process(input)
+	settle := sampleRate / 2
+	return float64(rmsDbfs(output[settle:]) - rmsDbfs(input[settle:]))
+}
+
+func TestKWeightingFilter(t *testing.T) {
+	filter, err := NewKWeightingFilter(48000)
+	require.NoError(t, err)
+	require.NotNil(t, filter)
+	defer filter.Close()
+
+	low := sineGainDb(filter, 48000, 100)
+	filter.Reset()
+	high := sineGainDb(filter, 48000, 2000)
+
+	// The shelf boosts 2kHz by about 3dB; the high-pass trims 100Hz by about 1dB
+	assert.InDelta(t, -1, low, 0.5)
+	assert.Greater(t, high-low, 2.5)
+	t.Logf("K-weighting gain: 100Hz %.2f dB, 2kHz %.2f dB", low, high)
+
+	_, err = NewKWeightingFilter(0)
+	assert.Error(t, err)
+}
+
+func TestHumFilter(t *testing.T) {
+	const rate = 16000
+	// Mains hum drifted slightly from nominal: 60.6Hz plus two harmonics
+	input := make([]int16, rate*4)
+	samples := make([]float64, len(input))
+	for i := range input {
+		ts := float64(i) / rate
+		v := 6000*math.Sin(2*math.Pi*60.6*ts) +
+			3000*math.Sin(2*math.Pi*121.2*ts) +
+			1500*math.Sin(2*math.Pi*181.8*ts)
+		input[i] = int16(v)
+	}
+
+	hum, err := NewHumFilter(rate, 60, 2)
+	require.NoError(t, err)
+	defer hum.Close()
+
+	var output []int16
+	for start := 0; start < len(input); start += 160 {
+		output = append(output, hum.Process(input[start:start+160])...)
+	}
+	require.Len(t, output, len(input))
+	assert.InDelta(t, 60.6, hum.BaseFrequency(), 0.2)
+
+	// Compare the hum components over the final second
+	tail := func(x []int16) []float64 {
+		for i, v := range x[len(x)-rate:] {
+			samples[i] = float64(v)
+		}
+		return samples[:rate]
+	}
+	for _, hz := range []float64{60.6, 121.2, 181.8} {
+		before := goertzelPower(tail(input), rate, hz)
+		after := goertzelPower(tail(output), rate, hz)
+		reduction := 10 * math.Log10(before/after)
+		assert.Greater(t, reduction, 20.0, "attenuation at %.1fHz", hz)
+	}
+}
+
+func TestHighPassFilter(t *testing.T) {
+	filter, err := NewHighPassFilter(16000, 200)
+	require.NoError(t, err)
+	defer filter.Close()
+
+	assert.InDelta(t, -3, sineGainDb(filter, 16000, 200), 0.3)
+	filter.Reset()
+	assert.InDelta(t, 0, sineGainDb(filter, 16000, 2000), 0.3)
+
+	_, err = NewHighPassFilter(16000, 8000)
+	assert.ErrorIs(t, err, ErrInvalidRange)
+}
@@ -0,0 +1,118 @@
+package sonickit
+
+// RecommendedFrameSize returns the number of samples in a frame of frameMs
+// milliseconds at sampleRate, rounded down. The VAD, and the processors
+// built on it such as DtxEncoder, accept only 10, 20 or 30ms frames, and
+// 10ms or 20ms frames suit the denoiser and echo canceller best. Returns 0
+// if sampleRate or frameMs is not positive.
+func RecommendedFrameSize(sampleRate, frameMs int) int {
+	if sampleRate <= 0 || frameMs <= 0 {
+		return 0
+	}
+	return sampleRate * frameMs / 1000
+}
+
+// frameChunker cuts arbitrary-length input into whole frames for the
+// fixed-frame native processors, carrying any remainder over to the next
+// call.
+type frameChunker struct {
+	frameSize int
+	pending   []int16 // samples short of a whole frame
+}
+
+// split returns the pending samples followed by input, trimmed to a whole
+// number of frames, and keeps the rest as pending. When nothing is pending
+// and input is already frame aligned, input is returned as is.
+func (c *frameChunker) split(input []int16) []int16 {
+	if len(c.pending) == 0 && len(input)%c.frameSize == 0 {
+		return input
+	}
+	data := make([]int16, len(c.pending)+len(input))
+	copy(data[copy(data, c.pending):], input)
+	whole := len(data) - len(data)%c.frameSize
+	c.pending = append(c.pending[:0], data[whole:]...)
+	return data[:whole]
+}
+
+// direct reports whether n samples can bypass split and go straight to the
+// native processor: they make whole frames, and no pending samples would be
+// overtaken by them.
+func (c *frameChunker) direct(n int) bool {
+	return n%c.frameSize == 0 && len(c.pending) == 0
+}
+
+// flush returns the pending samples zero-padded to one frame, along with
+// how many of them are real, and clears the pending samples. It returns
+// nil, 0 if nothing is pending.
+func (c *frameChunker) flush() ([]int16, int) {
+	if len(c.pending) == 0 {
+		return nil, 0
+	}
+	frame := make([]int16, c.frameSize)
+	n := copy(frame, c.pending)
+	c.pending = c.pending[:0]
+	return frame, n
+}
+
+// Framer cuts a continuous stream of interleaved PCM into frames of a fixed
+// duration, as needed to packetize audio for codecs such as Opus (which
+// takes 10, 20, 40 or 60ms frames).
+type Framer struct {
+	chunks   frameChunker
+	channels int
+}
+
+// NewFramer creates a new framer.
+//
+// Parameters:
+//   - sampleRate: Audio sample rate in Hz
+//   - frameMs: Frame duration in milliseconds
+//   - channels: Number of interleaved channels
+func NewFramer(sampleRate, frameMs, channels int) (*Framer, error) {
+	if err := validate("framer",
+		checkPositiveRate(sampleRate),
+		checkChannels(channels),
+		checkFrameSize(RecommendedFrameSize(sampleRate, frameMs)),
+	); err != nil {
+		return nil, err
+	}
+	return &Framer{
+		chunks:   frameChunker{frameSize: RecommendedFrameSize(sampleRate, frameMs) * channels},
+		channels: channels,
+	}, nil
+}
+
+// FrameSize returns the number of samples per channel in each frame.
+func (f *Framer) FrameSize() int {
+	return f.chunks.frameSize / f.channels
+}
+
+// Push appends samples to the stream and returns every frame completed so
+// far, each of FrameSize()*channels interleaved samples. Samples short of a
+// whole frame are held until a later Push completes it. The frames do not
+// alias samples.
+func (f *Framer) Push(samples []int16) [][]int16 {
+	data := f.chunks.split(samples)
+	if len(data) == 0 {
+		return nil
+	}
+	data = append([]int16(nil), data...)
+	size := f.chunks.frameSize
+	frames := make([][]int16, 0, len(data)/size)
+	for i := 0; i < len(data); i += size {
+		frames = append(frames, data[i:i+size:i+size])
+	}
+	return frames
+}
+
+// Flush returns the samples held back by Push, which are fewer than a
+// whole frame, and empties the framer. It returns nil if nothing is held.
+// Pad the result with silence if the codec needs a full final frame.
+func (f *Framer) Flush() []int16 {
+	if len(f.chunks.pending) == 0 {
+		return nil
+	}
+	rest := append([]int16(nil), f.chunks.pending...)
+	f.chunks.pending = f.chunks.pending[:0]
+	return rest
+}
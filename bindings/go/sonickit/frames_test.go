@@ -0,0 +1,57 @@
+package sonickit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFramer(t *testing.T) {
+	framer, err := NewFramer(48000, 20, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 960, framer.FrameSize())
+
+	var frames [][]int16
+	next := int16(0)
+	for _, n := range []int{500, 1700, 3, 1000, 2617} {
+		chunk := make([]int16, n)
+		for i := range chunk {
+			chunk[i] = next
+			next++
+		}
+		frames = append(frames, framer.Push(chunk)...)
+	}
+
+	// 5820 samples make three whole 1920-sample stereo frames
+	require.Len(t, frames, 3)
+	want := int16(0)
+	for _, frame := range frames {
+		require.Len(t, frame, 1920)
+		for _, s := range frame {
+			require.Equal(t, want, s)
+			want++
+		}
+	}
+	rest := framer.Flush()
+	assert.Len(t, rest, 60)
+	assert.Equal(t, want, rest[0])
+	assert.Nil(t, framer.Flush())
+
+	_, err = NewFramer(48000, 0, 2)
+	assert.ErrorIs(t, err, ErrInvalidFrameSize)
+}
+
+func TestRecommendedFrameSize(t *testing.T) {
+	assert.Equal(t, 320, RecommendedFrameSize(16000, 20))
+	assert.Equal(t, 480, RecommendedFrameSize(48000, 10))
+	assert.Equal(t, 0, RecommendedFrameSize(0, 20))
+
+	// The DTX encoder's VAD only takes 10, 20 or 30ms frames
+	_, err := NewDtxEncoder(16000, 300, VadQuality)
+	assert.ErrorIs(t, err, ErrInvalidFrameSize)
+	assert.ErrorContains(t, err, "nearest is 320")
+	enc, err := NewDtxEncoder(16000, RecommendedFrameSize(16000, 30), VadQuality)
+	require.NoError(t, err)
+	enc.Close()
+}
@@ -0,0 +1,59 @@
+package sonickit
+
+/*
+#include "voice/error.h"
+
+extern void goLogCallback(int level, char *msg);
+
+static void sonickit_log_bridge(voice_log_level_t level, const char *msg, void *user_data) {
+	(void)user_data;
+	goLogCallback((int)level, (char *)msg);
+}
+
+static void sonickit_install_log(void) {
+	voice_set_log_callback(sonickit_log_bridge, NULL);
+}
+*/
+import "C"
+import "sync/atomic"
+
+// Log levels passed to the handler registered with SetLogHandler.
+const (
+	LogDebug = iota
+	LogInfo
+	LogWarn
+	LogError
+)
+
+var logHandler atomic.Pointer[func(level int, msg string)]
+
+// SetLogHandler registers fn to receive diagnostic messages from the native
+// library. Pass nil to discard them. Until SetLogHandler is first called the
+// native library prints its messages to stdout and stderr.
+//
+// The handler may be called from any goroutine, including from within
+// constructors and Process calls, and must not call back into the processor
+// that is logging.
+func SetLogHandler(fn func(level int, msg string)) {
+	if fn == nil {
+		logHandler.Store(nil)
+	} else {
+		logHandler.Store(&fn)
+	}
+	// Stay installed even without a handler: removing the callback would
+	// send the native library back to printing on the console
+	C.sonickit_install_log()
+}
+
+// logLevel maps a native log level onto the LogDebug..LogError range.
+func logLevel(level C.int) int {
+	switch {
+	case level <= C.VOICE_LOG_DEBUG:
+		return LogDebug
+	case level == C.VOICE_LOG_INFO:
+		return LogInfo
+	case level == C.VOICE_LOG_WARN:
+		return LogWarn
+	}
+	return LogError
+}
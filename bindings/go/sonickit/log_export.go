@@ -0,0 +1,14 @@
+package sonickit
+
+import "C"
+
+// goLogCallback receives native log messages, dropping them while no
+// handler is set; it is kept apart from log.go because files with exports
+// may only declare C functions.
+//
+//export goLogCallback
+func goLogCallback(level C.int, msg *C.char) {
+	if fn := logHandler.Load(); fn != nil {
+		(*fn)(logLevel(level), C.GoString(msg))
+	}
+}
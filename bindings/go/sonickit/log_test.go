@@ -0,0 +1,48 @@
+package sonickit
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetLogHandler(t *testing.T) {
+	var mu sync.Mutex
+	var levels []int
+	var messages []string
+	SetLogHandler(func(level int, msg string) {
+		mu.Lock()
+		defer mu.Unlock()
+		levels = append(levels, level)
+		messages = append(messages, msg)
+	})
+	defer SetLogHandler(nil)
+
+	// The native G.722 codec logs its creation
+	codec, err := NewG722Codec()
+	require.NoError(t, err)
+	codec.Close()
+
+	mu.Lock()
+	require.NotEmpty(t, messages)
+	assert.Contains(t, levels, LogInfo)
+	for _, level := range levels {
+		assert.GreaterOrEqual(t, level, LogDebug)
+		assert.LessOrEqual(t, level, LogError)
+	}
+	mu.Unlock()
+
+	// With the handler removed, nothing more is delivered
+	SetLogHandler(nil)
+	mu.Lock()
+	seen := len(messages)
+	mu.Unlock()
+	codec, err = NewG722Codec()
+	require.NoError(t, err)
+	codec.Close()
+	mu.Lock()
+	assert.Len(t, messages, seen)
+	mu.Unlock()
+}
@@ -0,0 +1,115 @@
+package sonickit
+
+import "math"
+
+// ITU-R BS.1770 gating parameters.
+const (
+	loudnessBlockMs      = 400
+	loudnessHopMs        = 100
+	loudnessAbsoluteGate = -70.0 // LUFS
+	loudnessRelativeGate = -10.0 // LU below the absolute-gated loudness
+)
+
+// integratedLoudness returns the gated integrated loudness of mono samples
+// in LUFS per ITU-R BS.1770, or -Inf if every block falls below the
+// absolute gate. Input shorter than one gating block is measured as a
+// single block.
+func integratedLoudness(samples []int16, sampleRate int) float64 {
+	filter, err := NewKWeightingFilter(sampleRate)
+	if err != nil || len(samples) == 0 {
+		return math.Inf(-1)
+	}
+	squares := make([]float64, len(samples))
+	for i, s := range samples {
+		y := filter.processSample(float64(s) / 32768)
+		squares[i] = y * y
+	}
+
+	block := sampleRate * loudnessBlockMs / 1000
+	hop := sampleRate * loudnessHopMs / 1000
+	if block > len(squares) {
+		block = len(squares)
+	}
+	var powers []float64
+	for start := 0; start+block <= len(squares); start += hop {
+		var sum float64
+		for _, v := range squares[start : start+block] {
+			sum += v
+		}
+		powers = append(powers, sum/float64(block))
+	}
+
+	gated := func(threshold float64) (float64, int) {
+		var sum float64
+		n := 0
+		for _, p := range powers {
+			if blockLoudness(p) > threshold {
+				sum += p
+				n++
+			}
+		}
+		if n == 0 {
+			return 0, 0
+		}
+		return sum / float64(n), n
+	}
+
+	mean, n := gated(loudnessAbsoluteGate)
+	if n == 0 {
+		return math.Inf(-1)
+	}
+	if mean, n = gated(blockLoudness(mean) + loudnessRelativeGate); n == 0 {
+		return math.Inf(-1)
+	}
+	return blockLoudness(mean)
+}
+
+// blockLoudness converts a K-weighted mean square to LUFS.
+func blockLoudness(meanSquare float64) float64 {
+	if meanSquare <= 0 {
+		return math.Inf(-1)
+	}
+	return -0.691 + 10*math.Log10(meanSquare)
+}
+
+// NormalizePeak returns a copy of samples scaled so the highest absolute
+// sample reaches targetDbfs. Targets above 0dBFS are clamped to 0dBFS so
+// the result never clips. Silent input is returned unchanged.
+func NormalizePeak(samples []int16, targetDbfs float32) []int16 {
+	if len(samples) == 0 {
+		return nil
+	}
+	peak := 0
+	for _, s := range samples {
+		v := int(s)
+		if v < 0 {
+			v = -v
+		}
+		if v > peak {
+			peak = v
+		}
+	}
+	if peak == 0 {
+		return ApplyGain(samples, 0)
+	}
+	if targetDbfs > 0 {
+		targetDbfs = 0
+	}
+	peakDb := 20 * math.Log10(float64(peak)/32768)
+	return ApplyGain(samples, targetDbfs-float32(peakDb))
+}
+
+// NormalizeLUFS returns a copy of samples with a single gain applied so the
+// integrated loudness (ITU-R BS.1770, gated) reaches targetLufs. Samples
+// that would exceed full scale are clamped. Input that is silent, or a
+// non-positive sample rate, is returned unchanged.
+func NormalizeLUFS(samples []int16, sampleRate int, targetLufs float32) []int16 {
+	if len(samples) == 0 {
+		return nil
+	}
+	loudness := integratedLoudness(samples, sampleRate)
+	if math.IsInf(loudness, -1) {
+		return ApplyGain(samples, 0)
+	}
+	return ApplyGain(samples, targetLufs-float32(loudness))
+}
@@ -0,0 +1,52 @@
+package sonickit
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegratedLoudness(t *testing.T) {
+	// A full-scale 997Hz sine reads -3.01 LUFS in mono
+	const rate = 48000
+	tone := make([]int16, rate*2)
+	for i := range tone {
+		tone[i] = int16(32767 * math.Sin(2*math.Pi*997*float64(i)/rate))
+	}
+	assert.InDelta(t, -3.01, integratedLoudness(tone, rate), 0.1)
+	assert.True(t, math.IsInf(integratedLoudness(make([]int16, rate), rate), -1))
+}
+
+func TestNormalizePeak(t *testing.T) {
+	input := []int16{1000, -4000, 2000}
+
+	output := NormalizePeak(input, -6)
+	require.Len(t, output, len(input))
+	assert.InDelta(t, 16423, -int(output[1]), 2)
+	assert.InDelta(t, 4106, output[0], 2)
+
+	// Targets above full scale are clamped rather than clipping
+	assert.Equal(t, int16(math.MinInt16), NormalizePeak(input, 6)[1])
+	assert.Equal(t, []int16{0, 0}, NormalizePeak([]int16{0, 0}, -1))
+}
+
+func TestNormalizeLUFS(t *testing.T) {
+	const rate = 48000
+	speechLike := make([]int16, rate*3)
+	for i := range speechLike {
+		ts := float64(i) / rate
+		envelope := 0.5 + 0.5*math.Sin(2*math.Pi*3*ts)
+		speechLike[i] = int16(3000 * envelope * (math.Sin(2*math.Pi*200*ts) + 0.5*math.Sin(2*math.Pi*1200*ts)))
+	}
+
+	for _, target := range []float32{-23, -16} {
+		output := NormalizeLUFS(speechLike, rate, target)
+		require.Len(t, output, len(speechLike))
+		assert.InDelta(t, target, integratedLoudness(output, rate), 0.2)
+	}
+
+	silence := make([]int16, rate)
+	assert.Equal(t, silence, NormalizeLUFS(silence, rate, -23))
+}
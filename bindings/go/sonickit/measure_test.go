@@ -0,0 +1,56 @@
+package sonickit
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeasureTHDN(t *testing.T) {
+	// A clean sine carries only rounding noise
+	clean := Sine(48000, 1000, 0.5, 48000)
+	assert.Less(t, MeasureTHDN(clean, 48000, 1000), float32(-80))
+
+	// Clipping at half the peak adds strong odd harmonics
+	clipped := Sine(48000, 1000, 1, 48000)
+	for i, s := range clipped {
+		clipped[i] = max(-16384, min(16384, s))
+	}
+	thdn := MeasureTHDN(clipped, 48000, 1000)
+	assert.Greater(t, thdn, float32(-20))
+	assert.Less(t, thdn, float32(0))
+
+	// Mild clipping lands in between
+	mild := Sine(48000, 1000, 1, 48000)
+	for i, s := range mild {
+		mild[i] = max(-30000, min(30000, s))
+	}
+	assert.Greater(t, MeasureTHDN(mild, 48000, 1000), float32(-80))
+	assert.Less(t, MeasureTHDN(mild, 48000, 1000), thdn)
+
+	assert.Zero(t, MeasureTHDN(make([]int16, 100), 48000, 1000))
+}
+
+func TestFrequencyResponse(t *testing.T) {
+	filter, err := NewHighPassFilter(48000, 1000)
+	require.NoError(t, err)
+	defer filter.Close()
+
+	freqs := []float32{125, 250, 500, 1000, 2000, 4000, 8000}
+	gains := FrequencyResponse(filter, 48000, freqs)
+	require.Len(t, gains, len(freqs))
+	for i, hz := range freqs {
+		// Butterworth magnitude: r^4 / (1 + r^4) in power, r = f / cutoff
+		r4 := math.Pow(float64(hz)/1000, 4)
+		expected := 10 * math.Log10(r4/(1+r4))
+		assert.InDelta(t, expected, gains[i], 0.5, "%gHz", hz)
+	}
+	// 12dB per octave below the cutoff
+	assert.InDelta(t, 12, gains[1]-gains[0], 0.5)
+
+	gains = FrequencyResponse(filter, 48000, []float32{0, 24000})
+	assert.True(t, math.IsNaN(float64(gains[0])))
+	assert.True(t, math.IsNaN(float64(gains[1])))
+}
@@ -0,0 +1,179 @@
+package sonickit
+
+import "math"
+
+// vibratoMaxDelayMs is the delay sweep of Vibrato at full depth. At 5Hz it
+// bends the pitch by about a semitone either way.
+const vibratoMaxDelayMs = 5
+
+// Tremolo modulates the amplitude of the audio with a sine LFO.
+type Tremolo struct {
+	sampleRate int
+	rate       float64
+	depth      float64
+	phase      float64
+}
+
+// NewTremolo creates a new tremolo.
+//
+// Parameters:
+//   - sampleRate: Audio sample rate in Hz
+//   - rateHz: LFO rate in Hz (typically 2-10)
+//   - depth: Modulation depth (0.0-1.0); at 1.0 the level dips to silence
+func NewTremolo(sampleRate int, rateHz, depth float32) (*Tremolo, error) {
+	if err := validate("tremolo",
+		checkPositiveRate(sampleRate),
+		checkRange("rate", float64(rateHz), 0, float64(sampleRate)/2),
+		checkUnit("depth", depth),
+	); err != nil {
+		return nil, err
+	}
+	return &Tremolo{sampleRate: sampleRate, rate: float64(rateHz), depth: float64(depth)}, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (t *Tremolo) SampleRate() int {
+	return t.sampleRate
+}
+
+// SetRate sets the LFO rate in Hz, clamped to 0 through half the sample
+// rate. The LFO phase is continuous, so the rate can be changed without
+// clicks.
+func (t *Tremolo) SetRate(hz float32) {
+	t.rate = math.Max(0, math.Min(float64(t.sampleRate)/2, float64(hz)))
+}
+
+// SetDepth sets the modulation depth (0.0-1.0). Values outside that range
+// are clamped; beyond 1 the gain would go negative and flip the polarity.
+func (t *Tremolo) SetDepth(depth float32) {
+	t.depth = math.Max(0, math.Min(1, float64(depth)))
+}
+
+// Process applies tremolo to the audio.
+func (t *Tremolo) Process(input []int16) []int16 {
+	if len(input) == 0 {
+		return nil
+	}
+	output := make([]int16, len(input))
+	t.ProcessInto(output, input)
+	return output
+}
+
+// ProcessInto applies tremolo to src, writing the result to dst without
+// allocating. It returns len(src), or 0 if dst is shorter than src.
+func (t *Tremolo) ProcessInto(dst, src []int16) int {
+	if len(src) == 0 || len(dst) < len(src) {
+		return 0
+	}
+	step := 2 * math.Pi * t.rate / float64(t.sampleRate)
+	for i, s := range src {
+		gain := 1 - t.depth*(1+math.Sin(t.phase))/2
+		dst[i] = clampInt16(float64(s) * gain)
+		t.phase = math.Mod(t.phase+step, 2*math.Pi)
+	}
+	return len(src)
+}
+
+// Close is a no-op; Tremolo holds no native resources. It allows a Tremolo
+// to be used as a Processor.
+func (t *Tremolo) Close() error {
+	return nil
+}
+
+// Vibrato modulates the pitch of the audio with a sine LFO, by reading it
+// through a delay line whose length sweeps back and forth. Unlike Chorus
+// the dry signal is not mixed back in.
+type Vibrato struct {
+	sampleRate int
+	rate       float64
+	width      float64 // delay sweep in samples
+	phase      float64
+	line       []float64
+	pos        int
+}
+
+// NewVibrato creates a new vibrato.
+//
+// Parameters:
+//   - sampleRate: Audio sample rate in Hz
+//   - rateHz: LFO rate in Hz (typically 4-8)
+//   - depth: Modulation depth (0.0-1.0); 1.0 sweeps the delay by 5ms
+func NewVibrato(sampleRate int, rateHz, depth float32) (*Vibrato, error) {
+	if err := validate("vibrato",
+		checkPositiveRate(sampleRate),
+		checkRange("rate", float64(rateHz), 0, float64(sampleRate)/2),
+		checkUnit("depth", depth),
+	); err != nil {
+		return nil, err
+	}
+	maxWidth := float64(sampleRate) * vibratoMaxDelayMs / 1000
+	v := &Vibrato{
+		sampleRate: sampleRate,
+		rate:       float64(rateHz),
+		line:       make([]float64, int(maxWidth)+2),
+	}
+	v.SetDepth(depth)
+	return v, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (v *Vibrato) SampleRate() int {
+	return v.sampleRate
+}
+
+// SetRate sets the LFO rate in Hz, clamped to 0 through half the sample
+// rate. The LFO phase is continuous, so the rate can be changed without
+// clicks.
+func (v *Vibrato) SetRate(hz float32) {
+	v.rate = math.Max(0, math.Min(float64(v.sampleRate)/2, float64(hz)))
+}
+
+// SetDepth sets the modulation depth (0.0-1.0). Values outside that range
+// are clamped, since the delay line cannot sweep further.
+func (v *Vibrato) SetDepth(depth float32) {
+	d := math.Max(0, math.Min(1, float64(depth)))
+	v.width = d * float64(len(v.line)-2)
+}
+
+// Process applies vibrato to the audio.
+func (v *Vibrato) Process(input []int16) []int16 {
+	if len(input) == 0 {
+		return nil
+	}
+	output := make([]int16, len(input))
+	v.ProcessInto(output, input)
+	return output
+}
+
+// ProcessInto applies vibrato to src, writing the result to dst without
+// allocating. It returns len(src), or 0 if dst is shorter than src.
+func (v *Vibrato) ProcessInto(dst, src []int16) int {
+	if len(src) == 0 || len(dst) < len(src) {
+		return 0
+	}
+	size := len(v.line)
+	step := 2 * math.Pi * v.rate / float64(v.sampleRate)
+	for i, s := range src {
+		v.line[v.pos] = float64(s)
+		delay := v.width * (1 + math.Sin(v.phase)) / 2
+		whole := int(delay)
+		frac := delay - float64(whole)
+		a := v.line[(v.pos-whole+size)%size]
+		b := v.line[(v.pos-whole-1+size)%size]
+		dst[i] = clampInt16(a + (b-a)*frac)
+		v.pos = (v.pos + 1) % size
+		v.phase = math.Mod(v.phase+step, 2*math.Pi)
+	}
+	return len(src)
+}
+
+// Latency returns the average delay in samples, half the delay sweep.
+func (v *Vibrato) Latency() int {
+	return int(v.width / 2)
+}
+
+// Close is a no-op; Vibrato holds no native resources. It allows a Vibrato
+// to be used as a Processor.
+func (v *Vibrato) Close() error {
+	return nil
+}
@@ -0,0 +1,80 @@
+package sonickit
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTremolo(t *testing.T) {
+	const sampleRate = 16000
+	tremolo, err := NewTremolo(sampleRate, 5, 1)
+	require.NoError(t, err)
+
+	input := make([]int16, 2*sampleRate)
+	for i := range input {
+		input[i] = int16(10000 * math.Sin(2*math.Pi*1000*float64(i)/sampleRate))
+	}
+	output := tremolo.Process(input)
+	require.Len(t, output, len(input))
+
+	// Peak envelope over 10ms blocks: a 5Hz LFO repeats every 20 blocks
+	// and swings from silence to full level
+	block := sampleRate / 100
+	env := make([]float64, len(output)/block)
+	for b := range env {
+		for _, s := range output[b*block : (b+1)*block] {
+			env[b] = math.Max(env[b], math.Abs(float64(s)))
+		}
+	}
+	lo, hi := env[0], env[0]
+	for b, e := range env {
+		lo, hi = math.Min(lo, e), math.Max(hi, e)
+		if b+20 < len(env) {
+			assert.InDelta(t, e, env[b+20], 500, "block %d", b)
+		}
+	}
+	assert.Greater(t, hi, 9500.0)
+	assert.Less(t, lo, 1000.0)
+
+	_, err = NewTremolo(sampleRate, 5, 1.5)
+	assert.ErrorIs(t, err, ErrInvalidRange)
+
+	// SetDepth clamps, so the gain never goes negative and flips polarity
+	tremolo.SetDepth(2)
+	dc := make([]int16, sampleRate)
+	for i := range dc {
+		dc[i] = 10000
+	}
+	for _, s := range tremolo.Process(dc) {
+		require.GreaterOrEqual(t, s, int16(0))
+	}
+}
+
+func TestVibrato(t *testing.T) {
+	const sampleRate = 16000
+	vibrato, err := NewVibrato(sampleRate, 5, 1)
+	require.NoError(t, err)
+
+	input := make([]int16, sampleRate)
+	for i := range input {
+		input[i] = int16(10000 * math.Sin(2*math.Pi*440*float64(i)/sampleRate))
+	}
+	output := vibrato.Process(input)
+	require.Len(t, output, len(input))
+
+	detector, err := NewPitchDetector(sampleRate, 100, 1000)
+	require.NoError(t, err)
+	defer detector.Close()
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for start := 0; start+640 <= len(output); start += 320 {
+		hz, _ := detector.Process(output[start : start+640])
+		require.Greater(t, hz, float32(0))
+		lo, hi = math.Min(lo, float64(hz)), math.Max(hi, float64(hz))
+	}
+	// The pitch swings well either side of 440Hz
+	assert.Less(t, lo, 420.0)
+	assert.Greater(t, hi, 460.0)
+}
@@ -0,0 +1,90 @@
+package sonickit
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// netsimMaxJitterMs is the largest jitter a NetworkSimulator accepts.
+const netsimMaxJitterMs = 10000
+
+// Packet is an audio packet in transit, as passed to JitterBuffer.Put.
+type Packet struct {
+	Data      []int16 // Audio samples
+	Timestamp uint32  // RTP timestamp
+	Sequence  uint16  // RTP sequence number
+	SendMs    int     // Time the packet was sent, in milliseconds
+	ArrivalMs int     // Time the packet arrived, set by NetworkSimulator.Feed
+}
+
+// NetworkSimulator models an unreliable network for testing jitter buffer
+// integration: it drops packets, delays them by a random amount and
+// delivers some out of order. It is driven by its own seeded random
+// source, so a given seed always produces the same impairments.
+//
+// A typical test sends packets with SendMs spaced one frame apart, feeds
+// them through the simulator and then plays them into a JitterBuffer in
+// arrival order:
+//
+//	for _, p := range sim.Feed(packets) {
+//		jitter.Put(p.Data, p.Timestamp, p.Sequence)
+//	}
+type NetworkSimulator struct {
+	lossRate    float64
+	reorderRate float64
+	jitterMs    int
+	rng         *rand.Rand
+}
+
+// NewNetworkSimulator creates a new network simulator.
+//
+// Parameters:
+//   - lossRate: Probability that a packet is dropped (0.0-1.0)
+//   - reorderRate: Probability that a packet is held back behind the
+//     packet after it (0.0-1.0)
+//   - jitterMs: Maximum extra delay in milliseconds; each packet is delayed
+//     by a uniformly random 0 to jitterMs
+//   - seed: Random seed
+func NewNetworkSimulator(lossRate, reorderRate float32, jitterMs int, seed uint64) (*NetworkSimulator, error) {
+	if err := validate("network simulator",
+		checkUnit("loss rate", lossRate),
+		checkUnit("reorder rate", reorderRate),
+		checkRange("jitter", float64(jitterMs), 0, netsimMaxJitterMs),
+	); err != nil {
+		return nil, err
+	}
+	return &NetworkSimulator{
+		lossRate:    float64(lossRate),
+		reorderRate: float64(reorderRate),
+		jitterMs:    jitterMs,
+		rng:         rand.New(rand.NewSource(int64(seed))),
+	}, nil
+}
+
+// Feed sends packets across the simulated network and returns the ones
+// that arrive, in arrival order, with ArrivalMs set. Packets are sent at
+// their SendMs; when jitter exceeds the spacing between them they can
+// overtake one another, and reordering holds a packet back until just
+// after the next one arrives. The returned packets share Data with the
+// input.
+func (n *NetworkSimulator) Feed(packets []Packet) []Packet {
+	arrived := make([]Packet, 0, len(packets))
+	for _, p := range packets {
+		if n.rng.Float64() < n.lossRate {
+			continue
+		}
+		p.ArrivalMs = p.SendMs + n.rng.Intn(n.jitterMs+1)
+		arrived = append(arrived, p)
+	}
+	sort.SliceStable(arrived, func(i, j int) bool {
+		return arrived[i].ArrivalMs < arrived[j].ArrivalMs
+	})
+	for i := 0; i+1 < len(arrived); i++ {
+		if n.rng.Float64() < n.reorderRate {
+			arrived[i].ArrivalMs = arrived[i+1].ArrivalMs
+			arrived[i], arrived[i+1] = arrived[i+1], arrived[i]
+			i++
+		}
+	}
+	return arrived
+}
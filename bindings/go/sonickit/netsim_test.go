@@ -0,0 +1,67 @@
+package sonickit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPackets returns n 20ms packets of silence at 16kHz.
+func testPackets(n int) []Packet {
+	packets := make([]Packet, n)
+	for i := range packets {
+		packets[i] = Packet{
+			Data:      make([]int16, 320),
+			Timestamp: uint32(i * 320),
+			Sequence:  uint16(i),
+			SendMs:    i * 20,
+		}
+	}
+	return packets
+}
+
+func TestNetworkSimulator(t *testing.T) {
+	const total = 10000
+	sim, err := NewNetworkSimulator(0.1, 0, 0, 1)
+	require.NoError(t, err)
+	arrived := sim.Feed(testPackets(total))
+	assert.InDelta(t, 0.1, float64(total-len(arrived))/total, 0.01)
+
+	// Without jitter or reordering the survivors keep their order and
+	// arrive as they are sent
+	for i, p := range arrived {
+		assert.Equal(t, p.SendMs, p.ArrivalMs)
+		if i > 0 {
+			require.Greater(t, p.Sequence, arrived[i-1].Sequence)
+		}
+	}
+
+	// Jitter and reordering shuffle the order, but never deliver a packet
+	// before it was sent or out of arrival order
+	sim, err = NewNetworkSimulator(0, 0.05, 60, 1)
+	require.NoError(t, err)
+	arrived = sim.Feed(testPackets(1000))
+	require.Len(t, arrived, 1000)
+	late := 0
+	for i, p := range arrived {
+		assert.GreaterOrEqual(t, p.ArrivalMs, p.SendMs)
+		if i > 0 {
+			require.GreaterOrEqual(t, p.ArrivalMs, arrived[i-1].ArrivalMs)
+			if p.Sequence < arrived[i-1].Sequence {
+				late++
+			}
+		}
+	}
+	assert.Positive(t, late)
+
+	// The same seed replays the same impairments
+	again, err := NewNetworkSimulator(0, 0.05, 60, 1)
+	require.NoError(t, err)
+	assert.Equal(t, arrived, again.Feed(testPackets(1000)))
+
+	_, err = NewNetworkSimulator(1.5, 0, 0, 1)
+	assert.ErrorIs(t, err, ErrInvalidRange)
+	_, err = NewNetworkSimulator(0, 0, -1, 1)
+	assert.ErrorIs(t, err, ErrInvalidRange)
+}
@@ -0,0 +1,121 @@
+package sonickit
+
+import (
+	"math"
+	"math/rand"
+)
+
+// NoiseColor selects the spectrum of NoiseGenerator output.
+type NoiseColor int
+
+const (
+	// NoiseWhite has equal power per hertz (a flat spectrum).
+	NoiseWhite NoiseColor = 0
+	// NoisePink has equal power per octave, falling 3dB per octave. It
+	// sounds balanced and is the usual choice for acoustic measurement.
+	NoisePink NoiseColor = 1
+	// NoiseBrown falls 6dB per octave, a deep rumble like surf or wind.
+	NoiseBrown NoiseColor = 2
+)
+
+const (
+	// pinkRMS is the RMS of the pink filter's output for unit-variance
+	// white input.
+	pinkRMS = 3.04
+	// brownLeak keeps the brown noise integrator from drifting. Its
+	// corner sits a few tens of Hz up, below most program material.
+	brownLeak = 0.995
+	// noiseDefaultDb is the level a NoiseGenerator starts at.
+	noiseDefaultDb = -20
+)
+
+// NoiseGenerator produces white, pink or brown noise for testing,
+// measurement and effects. Unlike ComfortNoiseGenerator it has no VoIP
+// behavior: the level is fixed, and each generator has its own seed.
+type NoiseGenerator struct {
+	sampleRate int
+	color      NoiseColor
+	rng        *rand.Rand
+	gain       float64
+	pink       [7]float64 // pink filter state
+	brown      float64    // brown integrator state
+}
+
+// NewNoiseGenerator creates a new noise generator at -20dBFS RMS, seeded
+// with 1 so that a fresh generator always produces the same sequence.
+func NewNoiseGenerator(sampleRate int, color NoiseColor) (*NoiseGenerator, error) {
+	if err := validate("noise generator",
+		checkPositiveRate(sampleRate),
+		checkRange("color", float64(color), float64(NoiseWhite), float64(NoiseBrown)),
+	); err != nil {
+		return nil, err
+	}
+	g := &NoiseGenerator{
+		sampleRate: sampleRate,
+		color:      color,
+		rng:        rand.New(rand.NewSource(1)),
+	}
+	g.SetLevel(noiseDefaultDb)
+	return g, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (g *NoiseGenerator) SampleRate() int {
+	return g.sampleRate
+}
+
+// SetLevel sets the RMS level of the noise in dBFS. Peaks run about 12dB
+// above the RMS level and are clipped at full scale.
+func (g *NoiseGenerator) SetLevel(dbfs float32) {
+	g.gain = dbToGain(float64(dbfs)) * 32768
+}
+
+// SetSeed restarts the random sequence from seed. Generators with the same
+// color and seed produce identical output.
+func (g *NoiseGenerator) SetSeed(seed uint64) {
+	g.rng.Seed(int64(seed))
+	g.pink = [7]float64{}
+	g.brown = 0
+}
+
+// Generate returns n samples of noise.
+func (g *NoiseGenerator) Generate(n int) []int16 {
+	if n <= 0 {
+		return nil
+	}
+	output := make([]int16, n)
+	for i := range output {
+		output[i] = clampInt16(g.next() * g.gain)
+	}
+	return output
+}
+
+// next returns the next noise sample with unit RMS.
+func (g *NoiseGenerator) next() float64 {
+	w := g.rng.NormFloat64()
+	switch g.color {
+	case NoisePink:
+		// Paul Kellet's refined pink filter, a sum of one-pole sections
+		// accurate to within 0.05dB above 9Hz at 44.1kHz
+		p := &g.pink
+		p[0] = 0.99886*p[0] + w*0.0555179
+		p[1] = 0.99332*p[1] + w*0.0750759
+		p[2] = 0.96900*p[2] + w*0.1538520
+		p[3] = 0.86650*p[3] + w*0.3104856
+		p[4] = 0.55000*p[4] + w*0.5329522
+		p[5] = -0.7616*p[5] - w*0.0168980
+		out := p[0] + p[1] + p[2] + p[3] + p[4] + p[5] + p[6] + w*0.5362
+		p[6] = w * 0.115926
+		return out / pinkRMS
+	case NoiseBrown:
+		g.brown = brownLeak*g.brown + w
+		return g.brown * math.Sqrt(1-brownLeak*brownLeak)
+	default:
+		return w
+	}
+}
+
+// Close is a no-op; NoiseGenerator holds no native resources.
+func (g *NoiseGenerator) Close() error {
+	return nil
+}
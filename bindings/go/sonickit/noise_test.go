@@ -0,0 +1,46 @@
+package sonickit
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoiseGenerator(t *testing.T) {
+	const sampleRate = 16000
+	gen, err := NewNoiseGenerator(sampleRate, NoisePink)
+	require.NoError(t, err)
+	defer gen.Close()
+
+	gen.SetLevel(-20)
+	noise := gen.Generate(sampleRate)
+	require.Len(t, noise, sampleRate)
+	assert.InDelta(t, -20, rmsDbfs(noise), 1)
+
+	// Mean power density over the octaves 125Hz-4kHz, in dB: pink noise
+	// loses 3dB per octave
+	var bands []float64
+	for lo := 125.0; lo < 4000; lo *= 2 {
+		var power float64
+		var bins int
+		for hz := lo; hz < 2*lo; hz += 4 {
+			level := harmonicLevel(noise, sampleRate, hz)
+			power += level * level
+			bins++
+		}
+		bands = append(bands, 10*math.Log10(power/float64(bins)))
+	}
+	slope := (bands[len(bands)-1] - bands[0]) / float64(len(bands)-1)
+	assert.InDelta(t, -3, slope, 1, "bands %v", bands)
+
+	// The same seed reproduces the same noise
+	gen.SetSeed(42)
+	first := gen.Generate(1000)
+	gen.SetSeed(42)
+	assert.Equal(t, first, gen.Generate(1000))
+
+	_, err = NewNoiseGenerator(sampleRate, NoiseColor(7))
+	assert.Error(t, err)
+}
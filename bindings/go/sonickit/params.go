@@ -0,0 +1,216 @@
+package sonickit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParamInfo describes one automatable parameter of a processor.
+type ParamInfo struct {
+	Name    string
+	Min     float32
+	Max     float32
+	Default float32
+	Current float32
+}
+
+// Parameterized is implemented by processors whose parameters can be
+// enumerated and set by name, letting a host drive any of them generically
+// (e.g. for automation or presets). It is implemented by Reverb, Delay,
+// Chorus, Flanger, Compressor, and Equalizer.
+type Parameterized interface {
+	// Params returns the processor's parameters with their current values.
+	Params() []ParamInfo
+	// SetParam sets a parameter by name. It returns an error for an
+	// unknown name or a value outside [Min, Max].
+	SetParam(name string, value float32) error
+}
+
+// checkParam validates name and value against params.
+func checkParam(params []ParamInfo, name string, value float32) error {
+	for _, p := range params {
+		if p.Name != name {
+			continue
+		}
+		if value < p.Min || value > p.Max {
+			return fmt.Errorf("%w: parameter %q value %g not in [%g, %g]", ErrInvalidRange, name, value, p.Min, p.Max)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown parameter %q", name)
+}
+
+// Params returns the reverb parameters.
+func (r *Reverb) Params() []ParamInfo {
+	return []ParamInfo{
+		{Name: "roomSize", Min: 0, Max: 1, Default: 0.5, Current: r.roomSize},
+		{Name: "wetLevel", Min: 0, Max: 1, Default: 0.3, Current: r.wetLevel},
+		{Name: "earlyReflections", Min: 0, Max: 1, Default: reverbDefaultEarly, Current: r.early},
+		{Name: "decayTime", Min: 0.1, Max: 20, Default: reverbDefaultDecay, Current: r.decay},
+		{Name: "damping", Min: 0, Max: 1, Default: reverbDefaultDamping, Current: r.damping},
+		{Name: "preDelay", Min: 0, Max: 200, Default: reverbDefaultPreDelay, Current: r.preDelayMs},
+	}
+}
+
+// SetParam sets a reverb parameter by name.
+func (r *Reverb) SetParam(name string, value float32) error {
+	if err := checkParam(r.Params(), name, value); err != nil {
+		return err
+	}
+	switch name {
+	case "roomSize":
+		r.SetRoomSize(value)
+	case "wetLevel":
+		r.SetWetLevel(value)
+	case "earlyReflections":
+		r.SetEarlyReflectionsLevel(value)
+	case "decayTime":
+		r.SetDecayTime(value)
+	case "damping":
+		r.SetDamping(value)
+	case "preDelay":
+		r.SetPreDelay(value)
+	}
+	return nil
+}
+
+// Params returns the delay parameters.
+func (d *Delay) Params() []ParamInfo {
+	return []ParamInfo{
+		{Name: "delayTime", Min: 1, Max: 2000, Default: 250, Current: d.delayMs},
+		{Name: "feedback", Min: 0, Max: delayMaxFeedback, Default: 0.4, Current: d.feedback},
+		{Name: "mix", Min: 0, Max: 1, Default: effectDefaultMix, Current: d.mix},
+	}
+}
+
+// SetParam sets a delay parameter by name.
+func (d *Delay) SetParam(name string, value float32) error {
+	if err := checkParam(d.Params(), name, value); err != nil {
+		return err
+	}
+	switch name {
+	case "delayTime":
+		d.SetDelayTime(value)
+	case "feedback":
+		d.SetFeedback(value)
+	case "mix":
+		d.SetMix(value)
+	}
+	return nil
+}
+
+// Params returns the chorus parameters.
+func (c *Chorus) Params() []ParamInfo {
+	return []ParamInfo{
+		{Name: "depth", Min: 0, Max: 1, Default: 0.5, Current: c.depth},
+		{Name: "rate", Min: 0.1, Max: 10, Default: 1.5, Current: c.rate},
+		{Name: "mix", Min: 0, Max: 1, Default: effectDefaultMix, Current: c.mix},
+	}
+}
+
+// SetParam sets a chorus parameter by name.
+func (c *Chorus) SetParam(name string, value float32) error {
+	if err := checkParam(c.Params(), name, value); err != nil {
+		return err
+	}
+	switch name {
+	case "depth":
+		c.SetDepth(value)
+	case "rate":
+		c.SetRate(value)
+	case "mix":
+		c.SetMix(value)
+	}
+	return nil
+}
+
+// Params returns the flanger parameters.
+func (f *Flanger) Params() []ParamInfo {
+	return []ParamInfo{
+		{Name: "depth", Min: 0, Max: 1, Default: 0.5, Current: f.depth},
+		{Name: "rate", Min: 0.05, Max: 5, Default: 0.5, Current: f.rate},
+		{Name: "mix", Min: 0, Max: 1, Default: effectDefaultMix, Current: f.mix},
+	}
+}
+
+// SetParam sets a flanger parameter by name.
+func (f *Flanger) SetParam(name string, value float32) error {
+	if err := checkParam(f.Params(), name, value); err != nil {
+		return err
+	}
+	switch name {
+	case "depth":
+		f.SetDepth(value)
+	case "rate":
+		f.SetRate(value)
+	case "mix":
+		f.SetMix(value)
+	}
+	return nil
+}
+
+// Params returns the compressor parameters.
+func (c *Compressor) Params() []ParamInfo {
+	return []ParamInfo{
+		{Name: "threshold", Min: -60, Max: 0, Default: -20, Current: c.threshold},
+		{Name: "ratio", Min: 1, Max: 20, Default: 4, Current: c.ratio},
+		{Name: "attack", Min: 0.1, Max: 100, Default: 10, Current: c.attackMs},
+		{Name: "release", Min: 10, Max: 1000, Default: 100, Current: c.releaseMs},
+	}
+}
+
+// SetParam sets a compressor parameter by name.
+func (c *Compressor) SetParam(name string, value float32) error {
+	if err := checkParam(c.Params(), name, value); err != nil {
+		return err
+	}
+	switch name {
+	case "threshold":
+		c.SetThreshold(value)
+	case "ratio":
+		c.SetRatio(value)
+	case "attack":
+		c.SetAttack(value)
+	case "release":
+		c.SetRelease(value)
+	}
+	return nil
+}
+
+// Params returns the equalizer parameters: "band<i>.frequency",
+// "band<i>.gain", and "band<i>.q" for each band i.
+func (e *Equalizer) Params() []ParamInfo {
+	nyquist := float32(e.sampleRate) / 2
+	params := make([]ParamInfo, 0, 3*e.bands)
+	for band, cur := range e.settings {
+		prefix := "band" + strconv.Itoa(band) + "."
+		params = append(params,
+			ParamInfo{Name: prefix + "frequency", Min: 20, Max: nyquist,
+				Default: defaultEqFrequency(e.sampleRate, band, e.bands), Current: cur.frequency},
+			ParamInfo{Name: prefix + "gain", Min: -24, Max: 24, Default: 0, Current: cur.gain},
+			ParamInfo{Name: prefix + "q", Min: 0.1, Max: 10, Default: defaultEqQ, Current: cur.q},
+		)
+	}
+	return params
+}
+
+// SetParam sets an equalizer band parameter by name, e.g. "band2.gain".
+func (e *Equalizer) SetParam(name string, value float32) error {
+	if err := checkParam(e.Params(), name, value); err != nil {
+		return err
+	}
+	prefix, field, _ := strings.Cut(strings.TrimPrefix(name, "band"), ".")
+	band, _ := strconv.Atoi(prefix)
+	cur := e.settings[band]
+	switch field {
+	case "frequency":
+		cur.frequency = value
+	case "gain":
+		cur.gain = value
+	case "q":
+		cur.q = value
+	}
+	e.SetBand(band, cur.frequency, cur.gain, cur.q)
+	return nil
+}
@@ -0,0 +1,79 @@
+package sonickit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReverbParams(t *testing.T) {
+	reverb, err := NewReverb(48000, 0.5, 0.3)
+	require.NoError(t, err)
+	defer reverb.Close()
+
+	var p Parameterized = reverb
+	params := p.Params()
+	require.Len(t, params, 6)
+	assert.Equal(t, "roomSize", params[0].Name)
+	assert.Equal(t, float32(0.5), params[0].Current)
+	assert.Equal(t, float32(0), params[0].Min)
+	assert.Equal(t, float32(1), params[0].Max)
+
+	require.NoError(t, p.SetParam("roomSize", 0.8))
+	assert.Equal(t, float32(0.8), p.Params()[0].Current)
+
+	assert.Error(t, p.SetParam("roomSize", 1.5))
+	assert.Error(t, p.SetParam("size", 0.5))
+}
+
+func TestEqualizerParams(t *testing.T) {
+	eq, err := NewEqualizer(48000, 3)
+	require.NoError(t, err)
+	defer eq.Close()
+
+	params := eq.Params()
+	require.Len(t, params, 9)
+	assert.Equal(t, "band0.frequency", params[0].Name)
+	assert.InDelta(t, 60, params[0].Current, 0.01)
+	assert.InDelta(t, 12000, params[6].Current, 0.01)
+
+	require.NoError(t, eq.SetParam("band1.gain", -6))
+	assert.Equal(t, float32(-6), eq.Params()[4].Current)
+	assert.Error(t, eq.SetParam("band3.gain", 0))
+
+	// Every parameterized type accepts its own defaults
+	comp, err := NewCompressor(48000, -20, 4, 10, 100)
+	require.NoError(t, err)
+	defer comp.Close()
+	for _, p := range []Parameterized{eq, comp} {
+		for _, info := range p.Params() {
+			assert.NoError(t, p.SetParam(info.Name, info.Default), info.Name)
+		}
+	}
+}
+
+func TestCloneCopiesParams(t *testing.T) {
+	comp, err := NewCompressor(48000, -20, 4, 10, 100)
+	require.NoError(t, err)
+	defer comp.Close()
+	require.NoError(t, comp.SetParam("ratio", 8))
+
+	compClone, err := comp.Clone()
+	require.NoError(t, err)
+	defer compClone.Close()
+	assert.Equal(t, comp.Params(), compClone.Params())
+	require.NoError(t, compClone.SetParam("threshold", -30))
+	assert.NotEqual(t, comp.Params(), compClone.Params())
+
+	reverb, err := NewReverb(48000, 0.7, 0.4)
+	require.NoError(t, err)
+	defer reverb.Close()
+
+	reverbClone, err := reverb.Clone()
+	require.NoError(t, err)
+	defer reverbClone.Close()
+	assert.Equal(t, reverb.Params(), reverbClone.Params())
+	reverbClone.SetRoomSize(0.2)
+	assert.Equal(t, float32(0.7), reverb.Params()[0].Current)
+}
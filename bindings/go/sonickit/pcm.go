@@ -0,0 +1,152 @@
+package sonickit
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"sync"
+	"unsafe"
+)
+
+// Bounds of 24-bit samples carried in int32 containers, as used by the
+// Process32 methods. Those keep the resolution that Process would lose;
+// their input must lie within these bounds, and their output is clipped to
+// them.
+const (
+	Int24Min = -1 << 23
+	Int24Max = 1<<23 - 1
+)
+
+// hostLittleEndian reports whether the host stores integers little-endian,
+// in which case little-endian PCM can be copied without byte swapping.
+var hostLittleEndian = func() bool {
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 1
+}()
+
+// BytesToInt16 decodes 16-bit PCM bytes into samples. The input length must
+// be even.
+func BytesToInt16(b []byte, bigEndian bool) ([]int16, error) {
+	if len(b)%2 != 0 {
+		return nil, errors.New("PCM byte length must be even")
+	}
+	if len(b) == 0 {
+		return nil, nil
+	}
+	samples := make([]int16, len(b)/2)
+	if !bigEndian && hostLittleEndian {
+		// Byte-wise copy into the sample slice, so the source needs no
+		// particular alignment
+		copy(unsafe.Slice((*byte)(unsafe.Pointer(&samples[0])), len(b)), b)
+		return samples, nil
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+	if bigEndian {
+		order = binary.BigEndian
+	}
+	for i := range samples {
+		samples[i] = int16(order.Uint16(b[2*i:]))
+	}
+	return samples, nil
+}
+
+// Int16ToBytes encodes samples as 16-bit PCM bytes.
+func Int16ToBytes(s []int16, bigEndian bool) []byte {
+	if len(s) == 0 {
+		return nil
+	}
+	b := make([]byte, len(s)*2)
+	if !bigEndian && hostLittleEndian {
+		copy(b, unsafe.Slice((*byte)(unsafe.Pointer(&s[0])), len(b)))
+		return b
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+	if bigEndian {
+		order = binary.BigEndian
+	}
+	for i, v := range s {
+		order.PutUint16(b[2*i:], uint16(v))
+	}
+	return b
+}
+
+// int16ToFloat32 converts samples to the -1..1 float domain.
+func int16ToFloat32(samples []int16) []float32 {
+	output := make([]float32, len(samples))
+	for i, s := range samples {
+		output[i] = float32(s) / 32768
+	}
+	return output
+}
+
+// float32ToInt16 converts -1..1 float samples to int16, saturating values
+// outside that range.
+func float32ToInt16(samples []float32) []int16 {
+	output := make([]int16, len(samples))
+	for i, s := range samples {
+		output[i] = clampInt16(float64(s) * 32768)
+	}
+	return output
+}
+
+// DitherType selects the dither added when reducing float audio to int16.
+//
+// Reverb, Compressor and Equalizer process float audio natively, so their
+// ProcessFloat adds no quantization. Delay, Chorus and Flanger have no
+// native float path: their ProcessFloat converts to int16 with the dither
+// chosen by SetDither and back, clipping input beyond full scale.
+type DitherType int
+
+const (
+	// DitherNone rounds to the nearest step. Quiet signals pick up
+	// harmonic distortion correlated with the signal.
+	DitherNone DitherType = 0
+	// DitherRectangular adds uniform noise of one step peak to peak. It
+	// removes most distortion but lets the noise level vary with the signal.
+	DitherRectangular DitherType = 1
+	// DitherTriangular adds triangular noise of two steps peak to peak,
+	// which fully decorrelates the error from the signal. It is the usual
+	// choice.
+	DitherTriangular DitherType = 2
+	// DitherShaped is triangular dither with first-order noise shaping,
+	// moving the noise toward high frequencies where it is less audible at
+	// the cost of a higher total noise power.
+	DitherShaped DitherType = 3
+)
+
+// ditherRand is the noise source for dithering, seeded by SetDitherSeed.
+var (
+	ditherMu   sync.Mutex
+	ditherRand = rand.New(rand.NewSource(1))
+)
+
+// Float32ToInt16Dithered converts -1..1 float samples to int16 with the
+// given dither, saturating values outside that range. The noise comes from
+// a generator seeded with SetDitherSeed, so output is reproducible.
+func Float32ToInt16Dithered(in []float32, ditherType DitherType) []int16 {
+	if ditherType == DitherNone {
+		return float32ToInt16(in)
+	}
+	output := make([]int16, len(in))
+	ditherMu.Lock()
+	defer ditherMu.Unlock()
+	var shapeErr float64
+	for i, s := range in {
+		v := float64(s) * 32768
+		var noise float64
+		switch ditherType {
+		case DitherRectangular:
+			noise = ditherRand.Float64() - 0.5
+		default:
+			noise = ditherRand.Float64() - ditherRand.Float64()
+		}
+		if ditherType == DitherShaped {
+			v -= shapeErr
+		}
+		output[i] = clampInt16(v + noise)
+		shapeErr = float64(output[i]) - v
+	}
+	return output
+}
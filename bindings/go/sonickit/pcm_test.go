@@ -0,0 +1,84 @@
+package sonickit
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBytesToInt16(t *testing.T) {
+	le := []byte{0x01, 0x00, 0xff, 0xff, 0x34, 0x12, 0x00, 0x80}
+	be := []byte{0x00, 0x01, 0xff, 0xff, 0x12, 0x34, 0x80, 0x00}
+	want := []int16{1, -1, 0x1234, -32768}
+
+	samples, err := BytesToInt16(le, false)
+	require.NoError(t, err)
+	assert.Equal(t, want, samples)
+
+	samples, err = BytesToInt16(be, true)
+	require.NoError(t, err)
+	assert.Equal(t, want, samples)
+
+	// Unaligned source slice still decodes correctly
+	shifted := append([]byte{0}, le...)
+	samples, err = BytesToInt16(shifted[1:], false)
+	require.NoError(t, err)
+	assert.Equal(t, want, samples)
+
+	_, err = BytesToInt16(le[:3], false)
+	assert.Error(t, err)
+}
+
+func TestInt16ToBytes(t *testing.T) {
+	samples := []int16{1, -1, 0x1234, -32768}
+
+	assert.Equal(t, []byte{0x01, 0x00, 0xff, 0xff, 0x34, 0x12, 0x00, 0x80}, Int16ToBytes(samples, false))
+	assert.Equal(t, []byte{0x00, 0x01, 0xff, 0xff, 0x12, 0x34, 0x80, 0x00}, Int16ToBytes(samples, true))
+
+	for _, bigEndian := range []bool{false, true} {
+		back, err := BytesToInt16(Int16ToBytes(samples, bigEndian), bigEndian)
+		require.NoError(t, err)
+		assert.Equal(t, samples, back)
+	}
+	assert.Nil(t, Int16ToBytes(nil, false))
+}
+
+// harmonicLevel returns the DFT magnitude of samples at hz. samples must
+// span a whole number of periods.
+func harmonicLevel(samples []int16, sampleRate int, hz float64) float64 {
+	var re, im float64
+	for i, s := range samples {
+		phase := 2 * math.Pi * hz * float64(i) / float64(sampleRate)
+		re += float64(s) * math.Cos(phase)
+		im -= float64(s) * math.Sin(phase)
+	}
+	return math.Hypot(re, im)
+}
+
+func TestFloat32ToInt16Dithered(t *testing.T) {
+	// A 1kHz sine of 1.5 LSB, where plain rounding distorts badly
+	input := make([]float32, 48000)
+	for i := range input {
+		input[i] = float32(1.5 / 32768 * math.Sin(2*math.Pi*1000*float64(i)/48000))
+	}
+	distortion := func(samples []int16) float64 {
+		var harmonics float64
+		for h := 2.0; h <= 5; h++ {
+			harmonics += harmonicLevel(samples, 48000, 1000*h)
+		}
+		return harmonics / harmonicLevel(samples, 48000, 1000)
+	}
+
+	SetDitherSeed(1)
+	plain := distortion(Float32ToInt16Dithered(input, DitherNone))
+	triangular := distortion(Float32ToInt16Dithered(input, DitherTriangular))
+	assert.Less(t, triangular, plain/4)
+
+	// The same seed reproduces the same dither
+	SetDitherSeed(7)
+	first := Float32ToInt16Dithered(input, DitherShaped)
+	SetDitherSeed(7)
+	assert.Equal(t, first, Float32ToInt16Dithered(input, DitherShaped))
+}
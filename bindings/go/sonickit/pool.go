@@ -0,0 +1,50 @@
+package sonickit
+
+import (
+	"sync"
+	"unsafe"
+)
+
+// FramePool recycles fixed-size sample buffers so high-rate processing loops
+// can avoid allocating an output slice per frame. Pair it with the
+// ProcessInto methods, which write into a caller-supplied slice.
+//
+// Ownership: a slice returned by Get belongs to the caller until it is
+// passed to Put. After Put the caller must not read, write or retain the
+// slice (including sub-slices of it), since another Get may hand it out.
+//
+// A FramePool is safe for concurrent use.
+type FramePool struct {
+	frameSize int
+	pool      sync.Pool // holds the backing array as an unsafe.Pointer
+}
+
+// NewFramePool creates a pool of frameSize-sample buffers.
+func NewFramePool(frameSize int) *FramePool {
+	p := &FramePool{frameSize: frameSize}
+	p.pool.New = func() interface{} {
+		return unsafe.Pointer(unsafe.SliceData(make([]int16, frameSize)))
+	}
+	return p
+}
+
+// FrameSize returns the length of the slices handed out by Get.
+func (p *FramePool) FrameSize() int {
+	return p.frameSize
+}
+
+// Get returns a frameSize-sample slice. Its contents are unspecified.
+func (p *FramePool) Get() []int16 {
+	return unsafe.Slice((*int16)(p.pool.Get().(unsafe.Pointer)), p.frameSize)
+}
+
+// Put returns buf to the pool. Slices with a capacity below the pool's
+// frame size are dropped.
+func (p *FramePool) Put(buf []int16) {
+	if p.frameSize == 0 || cap(buf) < p.frameSize {
+		return
+	}
+	// Storing a bare pointer rather than a slice header keeps Put from
+	// allocating
+	p.pool.Put(unsafe.Pointer(unsafe.SliceData(buf)))
+}
@@ -0,0 +1,186 @@
+package sonickit
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFramePool(t *testing.T) {
+	pool := NewFramePool(160)
+	assert.Equal(t, 160, pool.FrameSize())
+
+	buf := pool.Get()
+	assert.Len(t, buf, 160)
+
+	// Short buffers are dropped; resliced ones come back full length
+	pool.Put(make([]int16, 10))
+	pool.Put(buf[:20])
+	assert.Len(t, pool.Get(), 160)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				b := pool.Get()
+				b[0] = 1
+				pool.Put(b)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestDenoiserProcessInto(t *testing.T) {
+	denoiser, err := NewDenoiser(16000, 160, DenoiserSpeexDSP)
+	require.NoError(t, err)
+	defer denoiser.Close()
+
+	pool := NewFramePool(160)
+	input := make([]int16, 160)
+	dst := pool.Get()
+	assert.Equal(t, 160, denoiser.ProcessInto(dst, input))
+	pool.Put(dst)
+
+	assert.Equal(t, 0, denoiser.ProcessInto(make([]int16, 80), input))
+}
+
+// intoProcessor is a Processor that can also write into a caller-supplied
+// slice.
+type intoProcessor interface {
+	Processor
+	ProcessInto(dst, src []int16) int
+}
+
+// intoProcessors lists a 16kHz constructor for each processor with
+// ProcessInto.
+var intoProcessors = []struct {
+	name   string
+	create func() (intoProcessor, error)
+}{
+	{"Denoiser", func() (intoProcessor, error) { return NewDenoiser(16000, 160, DenoiserSpeexDSP) }},
+	{"Agc", func() (intoProcessor, error) { return NewAgc(16000, 160, AgcAdaptive, -3) }},
+	{"Equalizer", func() (intoProcessor, error) { return NewEqualizer(16000, 3) }},
+	{"Compressor", func() (intoProcessor, error) { return NewCompressor(16000, -20, 4, 5, 50) }},
+	{"MultibandCompressor", func() (intoProcessor, error) { return NewMultibandCompressor(16000, []float32{300, 3000}) }},
+	{"FeedbackSuppressor", func() (intoProcessor, error) { return NewFeedbackSuppressor(16000, 4) }},
+	{"ClickRemover", func() (intoProcessor, error) { return NewClickRemover(16000) }},
+	{"SpectralGate", func() (intoProcessor, error) { return NewSpectralGate(16000, 256) }},
+	{"Reverb", func() (intoProcessor, error) { return NewReverb(16000, 0.5, 0.3) }},
+	{"ConvolutionReverb", func() (intoProcessor, error) { return NewConvolutionReverb(16000, Impulse(64, 0, 16384)) }},
+	{"Delay", func() (intoProcessor, error) { return NewDelay(16000, 5, 0.3) }},
+	{"Chorus", func() (intoProcessor, error) { return NewChorus(16000, 0.5, 1) }},
+	{"Flanger", func() (intoProcessor, error) { return NewFlanger(16000, 0.5, 0.5) }},
+	{"BitCrusher", func() (intoProcessor, error) { return NewBitCrusher(16000, 8, 2) }},
+	{"RingModulator", func() (intoProcessor, error) { return NewRingModulator(16000, 50) }},
+	{"AutoWah", func() (intoProcessor, error) { return NewAutoWah(16000, 1, 300, 2000, 3) }},
+	{"SubHarmonic", func() (intoProcessor, error) { return NewSubHarmonic(16000) }},
+	{"Filter", func() (intoProcessor, error) { return NewHighPassFilter(16000, 100) }},
+	{"HumFilter", func() (intoProcessor, error) { return NewHumFilter(16000, 50, 2) }},
+	{"Tremolo", func() (intoProcessor, error) { return NewTremolo(16000, 5, 0.5) }},
+	{"Vibrato", func() (intoProcessor, error) { return NewVibrato(16000, 5, 0.5) }},
+	{"Saturator", func() (intoProcessor, error) { return NewSaturator(16000, 2, SaturationTanh) }},
+}
+
+func TestProcessInto(t *testing.T) {
+	input := Sine(16000, 440, 0.3, 320)
+	for _, tc := range intoProcessors {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := tc.create()
+			require.NoError(t, err)
+			defer p.Close()
+			ref, err := tc.create()
+			require.NoError(t, err)
+			defer ref.Close()
+
+			// Same output as Process, into a dst with room to spare
+			dst := make([]int16, 400)
+			require.Equal(t, len(input), p.ProcessInto(dst, input))
+			assert.Equal(t, ref.Process(input), dst[:len(input)])
+			assert.Zero(t, p.ProcessInto(dst[:100], input))
+
+			allocs := testing.AllocsPerRun(100, func() {
+				p.ProcessInto(dst, input)
+			})
+			assert.Zero(t, allocs)
+		})
+	}
+}
+
+// frameSink keeps BenchmarkFrameAlloc's buffers on the heap.
+var frameSink atomic.Pointer[[]int16]
+
+// BenchmarkFrameAlloc is the baseline for BenchmarkFramePool: a fresh
+// buffer per frame.
+func BenchmarkFrameAlloc(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		var buf []int16
+		for pb.Next() {
+			buf = make([]int16, 960)
+		}
+		frameSink.Store(&buf)
+	})
+}
+
+func BenchmarkFramePool(b *testing.B) {
+	pool := NewFramePool(960)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			buf := pool.Get()
+			buf[0] = 1
+			pool.Put(buf)
+		}
+	})
+}
+
+func BenchmarkDenoiserProcess(b *testing.B) {
+	denoiser, err := NewDenoiser(16000, 160, DenoiserSpeexDSP)
+	require.NoError(b, err)
+	defer denoiser.Close()
+	input := make([]int16, 160)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = denoiser.Process(input)
+	}
+}
+
+func BenchmarkDenoiserProcessInto(b *testing.B) {
+	denoiser, err := NewDenoiser(16000, 160, DenoiserSpeexDSP)
+	require.NoError(b, err)
+	defer denoiser.Close()
+	input := make([]int16, 160)
+	pool := NewFramePool(160)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		out := pool.Get()
+		denoiser.ProcessInto(out, input)
+		pool.Put(out)
+	}
+}
+
+func BenchmarkProcessInto(b *testing.B) {
+	input := Sine(16000, 440, 0.3, 160)
+	dst := make([]int16, len(input))
+	for _, bc := range intoProcessors {
+		b.Run(bc.name, func(b *testing.B) {
+			p, err := bc.create()
+			require.NoError(b, err)
+			defer p.Close()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				p.ProcessInto(dst, input)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package sonickit
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// presetVersion is written to every preset so the format can evolve.
+const presetVersion = 1
+
+// preset is the JSON form of a pipeline saved by SavePreset.
+type preset struct {
+	Version int           `json:"version"`
+	Stages  []presetStage `json:"stages"`
+}
+
+// presetStage records how to recreate one stage.
+type presetStage struct {
+	Type       string             `json:"type"`
+	SampleRate int                `json:"sampleRate"`
+	Bands      int                `json:"bands,omitempty"`
+	Params     map[string]float32 `json:"params"`
+}
+
+// SavePreset serializes the pipeline's stage types and parameters as JSON.
+// Every stage must be one of the Parameterized types (Reverb, Delay,
+// Chorus, Flanger, Compressor, or Equalizer); otherwise an error is
+// returned.
+func (p *Pipeline) SavePreset() ([]byte, error) {
+	saved := preset{Version: presetVersion, Stages: make([]presetStage, 0, len(p.stages))}
+	for i, stage := range p.stages {
+		s := presetStage{Params: map[string]float32{}}
+		switch v := stage.(type) {
+		case *Reverb:
+			s.Type, s.SampleRate = "reverb", v.sampleRate
+		case *Delay:
+			s.Type, s.SampleRate = "delay", v.sampleRate
+		case *Chorus:
+			s.Type, s.SampleRate = "chorus", v.sampleRate
+		case *Flanger:
+			s.Type, s.SampleRate = "flanger", v.sampleRate
+		case *Compressor:
+			s.Type, s.SampleRate = "compressor", v.sampleRate
+		case *Equalizer:
+			s.Type, s.SampleRate, s.Bands = "equalizer", v.sampleRate, v.bands
+		default:
+			return nil, fmt.Errorf("stage %d: %T cannot be saved in a preset", i, stage)
+		}
+		for _, info := range stage.(Parameterized).Params() {
+			s.Params[info.Name] = info.Current
+		}
+		saved.Stages = append(saved.Stages, s)
+	}
+	return json.Marshal(saved)
+}
+
+// LoadPreset creates a pipeline from JSON written by SavePreset, creating
+// each processor and applying its saved parameters. Unknown stage types or
+// parameters are reported as errors.
+func LoadPreset(data []byte) (*Pipeline, error) {
+	var saved preset
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("parse preset: %w", err)
+	}
+	if saved.Version != presetVersion {
+		return nil, fmt.Errorf("unsupported preset version %d", saved.Version)
+	}
+
+	pipeline := NewPipeline()
+	for i, s := range saved.Stages {
+		stage, err := newPresetStage(s)
+		if err == nil {
+			for name, value := range s.Params {
+				if err = stage.(Parameterized).SetParam(name, value); err != nil {
+					stage.Close()
+					break
+				}
+			}
+		}
+		if err != nil {
+			pipeline.Close()
+			return nil, fmt.Errorf("preset stage %d (%s): %w", i, s.Type, err)
+		}
+		pipeline.Add(stage)
+	}
+	return pipeline, nil
+}
+
+// newPresetStage creates a processor of the saved type with default
+// settings; the saved parameters are applied afterwards.
+func newPresetStage(s presetStage) (Processor, error) {
+	switch s.Type {
+	case "reverb":
+		return NewReverb(s.SampleRate, 0.5, 0.3)
+	case "delay":
+		return NewDelay(s.SampleRate, 250, 0.4)
+	case "chorus":
+		return NewChorus(s.SampleRate, 0.5, 1.5)
+	case "flanger":
+		return NewFlanger(s.SampleRate, 0.5, 0.5)
+	case "compressor":
+		return NewCompressor(s.SampleRate, -20, 4, 10, 100)
+	case "equalizer":
+		return NewEqualizer(s.SampleRate, s.Bands)
+	}
+	return nil, fmt.Errorf("unknown stage type %q", s.Type)
+}
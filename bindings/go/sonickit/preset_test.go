@@ -0,0 +1,82 @@
+package sonickit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresetRoundTrip(t *testing.T) {
+	comp, err := NewCompressor(48000, -18, 3, 5, 200)
+	require.NoError(t, err)
+	eq, err := NewEqualizer(48000, 3)
+	require.NoError(t, err)
+	require.NoError(t, eq.SetParam("band1.gain", 4.5))
+	reverb, err := NewReverb(48000, 0.7, 0.25)
+	require.NoError(t, err)
+
+	pipeline := NewPipeline(comp, eq, reverb)
+	defer pipeline.Close()
+
+	data, err := pipeline.SavePreset()
+	require.NoError(t, err)
+
+	loaded, err := LoadPreset(data)
+	require.NoError(t, err)
+	defer loaded.Close()
+
+	require.Len(t, loaded.Stages(), 3)
+	for i, stage := range pipeline.Stages() {
+		want := stage.(Parameterized).Params()
+		got, ok := loaded.Stages()[i].(Parameterized)
+		require.True(t, ok)
+		assert.IsType(t, stage, loaded.Stages()[i])
+		assert.Equal(t, want, got.Params())
+	}
+}
+
+func TestPresetMix(t *testing.T) {
+	delay, err := NewDelay(48000, 5, 0.3)
+	require.NoError(t, err)
+	delay.SetMix(0.2)
+	chorus, err := NewChorus(48000, 0.5, 1.5)
+	require.NoError(t, err)
+	chorus.SetMix(0.8)
+	flanger, err := NewFlanger(48000, 0.5, 0.5)
+	require.NoError(t, err)
+	flanger.SetMix(0)
+
+	pipeline := NewPipeline(delay, chorus, flanger)
+	defer pipeline.Close()
+	data, err := pipeline.SavePreset()
+	require.NoError(t, err)
+	loaded, err := LoadPreset(data)
+	require.NoError(t, err)
+	defer loaded.Close()
+
+	// The reloaded pipeline has the saved mix, so it sounds the same
+	for i, stage := range pipeline.Stages() {
+		assert.Equal(t, stage.(Parameterized).Params(), loaded.Stages()[i].(Parameterized).Params())
+	}
+	input := Sine(48000, 440, 0.5, 4800)
+	assert.Equal(t, pipeline.Process(input), loaded.Process(input))
+}
+
+func TestPresetErrors(t *testing.T) {
+	shifter, err := NewPitchShifter(48000, 2)
+	require.NoError(t, err)
+	pipeline := NewPipeline(shifter)
+	defer pipeline.Close()
+	_, err = pipeline.SavePreset()
+	assert.Error(t, err)
+
+	_, err = LoadPreset([]byte(`{"version":1,"stages":[{"type":"phaser","sampleRate":48000}]}`))
+	assert.ErrorContains(t, err, `unknown stage type "phaser"`)
+
+	_, err = LoadPreset([]byte(`{"version":1,"stages":[{"type":"reverb","sampleRate":48000,"params":{"size":1}}]}`))
+	assert.Error(t, err)
+
+	_, err = LoadPreset([]byte(`not json`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,268 @@
+package sonickit
+
+import (
+	"errors"
+	"sync"
+)
+
+// Processor is implemented by processors that transform a block of samples,
+// such as Denoiser, Agc, Equalizer, Compressor, and the effects.
+type Processor interface {
+	Process(input []int16) []int16
+	Close() error
+}
+
+// LatencyReporter is implemented by processors that can report the delay,
+// in samples, between input and the corresponding output.
+type LatencyReporter interface {
+	Latency() int
+}
+
+// Pipeline runs a chain of processors in order.
+type Pipeline struct {
+	stages []Processor
+}
+
+// NewPipeline creates a pipeline from the given stages.
+func NewPipeline(stages ...Processor) *Pipeline {
+	return &Pipeline{stages: stages}
+}
+
+// Add appends a stage to the end of the pipeline.
+func (p *Pipeline) Add(stage Processor) {
+	p.stages = append(p.stages, stage)
+}
+
+// Stages returns the processors in the pipeline, in order.
+func (p *Pipeline) Stages() []Processor {
+	return p.stages
+}
+
+// Process runs input through every stage in order.
+func (p *Pipeline) Process(input []int16) []int16 {
+	output := input
+	for _, stage := range p.stages {
+		output = stage.Process(output)
+	}
+	return output
+}
+
+// Latency returns the total latency in samples of all stages. Stages that do
+// not implement LatencyReporter are assumed to add none.
+func (p *Pipeline) Latency() int {
+	total := 0
+	for _, stage := range p.stages {
+		if lr, ok := stage.(LatencyReporter); ok {
+			total += lr.Latency()
+		}
+	}
+	return total
+}
+
+// Close releases every stage, returning the first error encountered.
+func (p *Pipeline) Close() error {
+	var first error
+	for _, stage := range p.stages {
+		if err := stage.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	p.stages = nil
+	return first
+}
+
+// RateAdapter runs a Processor at a different sample rate from the
+// surrounding stream, e.g. an RNNoise Denoiser (48kHz only) in a 16kHz
+// capture path. Input is resampled to the processing rate, processed in
+// fixed blocks, and resampled back.
+//
+// Process always returns as many samples as it was given. Until enough
+// audio has passed through the resamplers and the wrapped processor, the
+// output is silence; this priming delay is included in Latency.
+type RateAdapter struct {
+	p         Processor
+	up        *Resampler
+	down      *Resampler
+	inRate    int
+	procRate  int
+	procBlock int     // processing block size, fixed by the first Process
+	upBuf     []int16 // upsampled input waiting for a full block
+	outBuf    []int16 // processed output at the stream rate
+	primed    bool
+	priming   int // silent samples emitted before the first real output
+}
+
+// NewRateAdapter wraps p, which must run at procRate, for use in a stream
+// at inRate. The adapter owns p; Close releases it along with the internal
+// resamplers.
+//
+// Parameters:
+//   - p: Processor running at procRate
+//   - inRate: Sample rate of the stream in Hz
+//   - procRate: Sample rate p expects in Hz
+//   - quality: Resampling quality (0-10, higher is better)
+func NewRateAdapter(p Processor, inRate, procRate int, quality int) (*RateAdapter, error) {
+	if p == nil {
+		return nil, errors.New("rate adapter requires a processor")
+	}
+	if inRate <= 0 || procRate <= 0 {
+		return nil, errors.New("sample rates must be positive")
+	}
+	up, err := NewResampler(1, inRate, procRate, quality)
+	if err != nil {
+		return nil, err
+	}
+	down, err := NewResampler(1, procRate, inRate, quality)
+	if err != nil {
+		up.Close()
+		return nil, err
+	}
+	return &RateAdapter{p: p, up: up, down: down, inRate: inRate, procRate: procRate}, nil
+}
+
+// SampleRate returns the stream sample rate in Hz, at which Process takes
+// and returns audio.
+func (a *RateAdapter) SampleRate() int {
+	return a.inRate
+}
+
+// Process resamples input to the processing rate, runs the wrapped
+// processor, and resamples the result back. The output has len(input)
+// samples.
+//
+// The wrapped processor is fed blocks of len(input) samples converted to
+// the processing rate (e.g. 160 at 16kHz becomes 480 at 48kHz), fixed by
+// the first call, so Process should be called with a constant frame size.
+func (a *RateAdapter) Process(input []int16) []int16 {
+	if a.up == nil || len(input) == 0 {
+		return nil
+	}
+	if a.procBlock == 0 {
+		a.procBlock = len(input) * a.procRate / a.inRate
+		if a.procBlock == 0 {
+			a.procBlock = 1
+		}
+	}
+
+	a.upBuf = append(a.upBuf, a.up.Process(input)...)
+	for len(a.upBuf) >= a.procBlock {
+		// processed may alias upBuf, so resample it before shifting
+		processed := a.p.Process(a.upBuf[:a.procBlock])
+		if len(processed) > 0 {
+			a.outBuf = append(a.outBuf, a.down.Process(processed)...)
+		}
+		a.upBuf = a.upBuf[:copy(a.upBuf, a.upBuf[a.procBlock:])]
+	}
+
+	// Hold back one extra frame once primed so the varying resampler
+	// output sizes never run the buffer dry
+	output := make([]int16, len(input))
+	if !a.primed {
+		if len(a.outBuf) < 2*len(input) {
+			a.priming += len(input)
+			return output
+		}
+		a.primed = true
+	}
+	n := copy(output, a.outBuf)
+	a.outBuf = a.outBuf[:copy(a.outBuf, a.outBuf[n:])]
+	return output
+}
+
+// Latency returns the total delay in samples at the stream rate: the
+// priming silence plus the wrapped processor's own latency, if it reports
+// one.
+func (a *RateAdapter) Latency() int {
+	latency := a.priming
+	if lr, ok := a.p.(LatencyReporter); ok {
+		latency += lr.Latency() * a.inRate / a.procRate
+	}
+	return latency
+}
+
+// Close releases the wrapped processor and both resamplers, returning the
+// first error encountered.
+func (a *RateAdapter) Close() error {
+	if a.up == nil {
+		return nil
+	}
+	err := a.p.Close()
+	a.up.Close()
+	a.down.Close()
+	a.up, a.down = nil, nil
+	return err
+}
+
+// NewSynchronized wraps p so that Process and Close may be called from
+// multiple goroutines. Calls are serialized with a mutex.
+func NewSynchronized(p Processor) Processor {
+	return &syncProcessor{p: p}
+}
+
+type syncProcessor struct {
+	mu sync.Mutex
+	p  Processor
+}
+
+func (s *syncProcessor) Process(input []int16) []int16 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.p.Process(input)
+}
+
+// Latency returns the wrapped processor's latency, or 0 if it does not
+// implement LatencyReporter.
+func (s *syncProcessor) Latency() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if lr, ok := s.p.(LatencyReporter); ok {
+		return lr.Latency()
+	}
+	return 0
+}
+
+func (s *syncProcessor) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.p.Close()
+}
+
+// SyncDenoiser is a Denoiser guarded by a mutex, safe for concurrent use.
+type SyncDenoiser struct {
+	mu sync.Mutex
+	d  *Denoiser
+}
+
+// Synchronized returns a wrapper around d that serializes Process, SetLevel,
+// and Close. d should not be used directly afterwards.
+func (d *Denoiser) Synchronized() *SyncDenoiser {
+	return &SyncDenoiser{d: d}
+}
+
+// Process applies noise reduction to the input samples.
+func (s *SyncDenoiser) Process(input []int16) []int16 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.d.Process(input)
+}
+
+// Flush drains the partial frame held back by Process.
+func (s *SyncDenoiser) Flush() []int16 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.d.Flush()
+}
+
+// SetLevel sets the noise reduction level (0-100).
+func (s *SyncDenoiser) SetLevel(level int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.d.SetLevel(level)
+}
+
+// Close releases the denoiser resources.
+func (s *SyncDenoiser) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.d.Close()
+}
@@ -0,0 +1,125 @@
+package sonickit
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSynchronized(t *testing.T) {
+	denoiser, err := NewDenoiser(16000, 160, DenoiserSpeexDSP)
+	require.NoError(t, err)
+	sd := denoiser.Synchronized()
+	defer sd.Close()
+
+	eq, err := NewEqualizer(16000, 3)
+	require.NoError(t, err)
+	sp := NewSynchronized(eq)
+	defer sp.Close()
+
+	input := make([]int16, 160)
+	for i := range input {
+		input[i] = int16(i * 100)
+	}
+
+	// Run with -race to verify the wrappers serialize access
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(level int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				sd.SetLevel(level)
+				assert.Len(t, sd.Process(input), len(input))
+				assert.Len(t, sp.Process(input), len(input))
+			}
+		}(g * 10)
+	}
+	wg.Wait()
+}
+
+func TestPipelineLatency(t *testing.T) {
+	comp, err := NewCompressor(48000, -20, 4.0, 10, 100)
+	require.NoError(t, err)
+	assert.Equal(t, 0, comp.Latency())
+
+	shifter, err := NewPitchShifter(48000, 3.0)
+	require.NoError(t, err)
+	assert.Greater(t, shifter.Latency(), 0)
+
+	pipeline := NewPipeline(comp, shifter)
+	defer pipeline.Close()
+	assert.Equal(t, shifter.Latency(), pipeline.Latency())
+
+	// Whole pitch shifter blocks pass straight through
+	input := make([]int16, 2*pitchBlockSize)
+	assert.Len(t, pipeline.Process(input), len(input))
+
+	// Synchronized stages report the latency of what they wrap
+	synced := NewPipeline(NewSynchronized(shifter))
+	assert.Equal(t, shifter.Latency(), synced.Latency())
+}
+
+func TestRateAdapter(t *testing.T) {
+	denoiser, err := NewDenoiser(48000, 480, DenoiserRNNoise)
+	if err != nil {
+		t.Skip("RNNoise not available:", err)
+	}
+	var frames []int
+	recorder := &recordingProcessor{Processor: denoiser, sizes: &frames}
+
+	adapter, err := NewRateAdapter(recorder, 16000, 48000, 5)
+	require.NoError(t, err)
+	defer adapter.Close()
+
+	input := make([]int16, 160)
+	for i := 0; i < 100; i++ {
+		for j := range input {
+			input[j] = int16((i*160 + j) % 200 * 50)
+		}
+		assert.Len(t, adapter.Process(input), len(input))
+	}
+
+	// RNNoise always received whole 10ms frames at 48kHz
+	require.NotEmpty(t, frames)
+	for _, n := range frames {
+		assert.Equal(t, 480, n)
+	}
+	assert.Greater(t, adapter.Latency(), 0)
+}
+
+func TestRateAdapterInPlace(t *testing.T) {
+	adapter, err := NewRateAdapter(passthroughProcessor{}, 44100, 48000, 5)
+	require.NoError(t, err)
+	defer adapter.Close()
+
+	// 160 samples at 44.1kHz do not resample to a whole number, so blocks
+	// are left over in the adapter between calls
+	tone := Sine(44100, 440, 0.5, 44100)
+	var output []int16
+	for i := 0; i+160 <= len(tone); i += 160 {
+		output = append(output, adapter.Process(tone[i:i+160])...)
+	}
+	assert.Less(t, MeasureTHDN(output[len(output)/2:], 44100, 440), float32(-30))
+}
+
+// passthroughProcessor returns its input slice unchanged.
+type passthroughProcessor struct{}
+
+func (passthroughProcessor) Process(input []int16) []int16 { return input }
+
+func (passthroughProcessor) Close() error { return nil }
+
+// recordingProcessor records the block sizes passed to the wrapped
+// processor.
+type recordingProcessor struct {
+	Processor
+	sizes *[]int
+}
+
+func (r *recordingProcessor) Process(input []int16) []int16 {
+	*r.sizes = append(*r.sizes, len(input))
+	return r.Processor.Process(input)
+}
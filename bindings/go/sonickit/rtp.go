@@ -0,0 +1,92 @@
+package sonickit
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// rtpHeaderSize is the size of the fixed RTP header (RFC 3550 section 5.1).
+const rtpHeaderSize = 12
+
+// rtpPacket holds the fields of an RTP packet used by the jitter buffer.
+type rtpPacket struct {
+	sequence  uint16
+	timestamp uint32
+	payload   []byte
+}
+
+// parseRTP parses an RTP packet, skipping any CSRC list and header
+// extension and stripping padding. The payload aliases packet.
+func parseRTP(packet []byte) (rtpPacket, error) {
+	if len(packet) < rtpHeaderSize {
+		return rtpPacket{}, fmt.Errorf("RTP packet too short: %d bytes", len(packet))
+	}
+	if version := packet[0] >> 6; version != 2 {
+		return rtpPacket{}, fmt.Errorf("unsupported RTP version %d", version)
+	}
+	hasPadding := packet[0]&0x20 != 0
+	hasExtension := packet[0]&0x10 != 0
+	csrcCount := int(packet[0] & 0x0f)
+
+	offset := rtpHeaderSize + 4*csrcCount
+	if len(packet) < offset {
+		return rtpPacket{}, errors.New("RTP packet truncated in CSRC list")
+	}
+	if hasExtension {
+		if len(packet) < offset+4 {
+			return rtpPacket{}, errors.New("RTP packet truncated in extension header")
+		}
+		extLen := int(binary.BigEndian.Uint16(packet[offset+2:])) * 4
+		offset += 4 + extLen
+		if len(packet) < offset {
+			return rtpPacket{}, errors.New("RTP packet truncated in extension")
+		}
+	}
+
+	end := len(packet)
+	if hasPadding {
+		padding := int(packet[end-1])
+		if padding == 0 || end-padding < offset {
+			return rtpPacket{}, fmt.Errorf("invalid RTP padding length %d", padding)
+		}
+		end -= padding
+	}
+
+	return rtpPacket{
+		sequence:  binary.BigEndian.Uint16(packet[2:]),
+		timestamp: binary.BigEndian.Uint32(packet[4:]),
+		payload:   packet[offset:end],
+	}, nil
+}
+
+// SetDecoder sets the function PutRTP uses to turn RTP payloads into PCM,
+// for example G711Codec.Decode. Passing nil restores the default 16-bit
+// linear PCM (L16) handling. The decoder runs synchronously on the
+// goroutine that calls PutRTP; Get always returns PCM.
+func (j *JitterBuffer) SetDecoder(decode func(payload []byte) []int16) {
+	j.decoder = decode
+}
+
+// PutRTP parses an RTP packet, decodes its payload and adds it to the
+// jitter buffer using the packet's sequence number and timestamp. Without a
+// decoder set by SetDecoder, the payload is taken to be 16-bit linear PCM
+// in network byte order (L16, RFC 3551). An error is returned for malformed
+// packets.
+func (j *JitterBuffer) PutRTP(packet []byte) error {
+	if j.handle == nil {
+		return errors.New("jitter buffer is closed")
+	}
+	p, err := parseRTP(packet)
+	if err != nil {
+		return err
+	}
+	var samples []int16
+	if j.decoder != nil {
+		samples = j.decoder(p.payload)
+	} else if samples, err = BytesToInt16(p.payload, true); err != nil {
+		return fmt.Errorf("RTP payload: %w", err)
+	}
+	j.Put(samples, p.timestamp, p.sequence)
+	return nil
+}
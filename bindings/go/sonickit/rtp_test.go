@@ -0,0 +1,110 @@
+package sonickit
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildRTP assembles an RTP packet with the given CSRC count and optional
+// one-word header extension.
+func buildRTP(seq uint16, ts uint32, csrcs int, extension bool, payload []byte) []byte {
+	b0 := byte(0x80) | byte(csrcs)
+	if extension {
+		b0 |= 0x10
+	}
+	packet := []byte{b0, 0, 0, 0, 0, 0, 0, 0, 0x12, 0x34, 0x56, 0x78}
+	binary.BigEndian.PutUint16(packet[2:], seq)
+	binary.BigEndian.PutUint32(packet[4:], ts)
+	packet = append(packet, make([]byte, 4*csrcs)...)
+	if extension {
+		packet = append(packet, 0xbe, 0xde, 0x00, 0x01, 1, 2, 3, 4)
+	}
+	return append(packet, payload...)
+}
+
+func TestParseRTP(t *testing.T) {
+	payload := []byte{0x01, 0x00, 0xff, 0xff}
+	packet := buildRTP(7, 960, 2, true, payload)
+
+	p, err := parseRTP(packet)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(7), p.sequence)
+	assert.Equal(t, uint32(960), p.timestamp)
+	assert.Equal(t, payload, p.payload)
+
+	// Padding is stripped
+	padded := append(buildRTP(8, 1280, 0, false, payload), 0, 0, 3)
+	padded[0] |= 0x20
+	p, err = parseRTP(padded)
+	require.NoError(t, err)
+	assert.Equal(t, payload, p.payload)
+
+	_, err = parseRTP(packet[:8])
+	assert.Error(t, err, "short header")
+	_, err = parseRTP(buildRTP(1, 0, 3, false, nil)[:16])
+	assert.Error(t, err, "truncated CSRC list")
+	_, err = parseRTP(buildRTP(1, 0, 0, true, nil)[:18])
+	assert.Error(t, err, "truncated extension")
+	bad := buildRTP(1, 0, 0, false, payload)
+	bad[0] = 0x40
+	_, err = parseRTP(bad)
+	assert.Error(t, err, "version 1")
+}
+
+func TestJitterBufferPutRTP(t *testing.T) {
+	jitter, err := NewJitterBuffer(16000, 20, 40, 200)
+	require.NoError(t, err)
+	defer jitter.Close()
+
+	pcm := make([]int16, 320)
+	for i := range pcm {
+		pcm[i] = int16(i * 10)
+	}
+	for seq := uint16(0); seq < 5; seq++ {
+		packet := buildRTP(seq, uint32(seq)*320, 1, true, Int16ToBytes(pcm, true))
+		require.NoError(t, jitter.PutRTP(packet))
+	}
+	assert.Len(t, jitter.Get(320), 320)
+
+	assert.Error(t, jitter.PutRTP([]byte{0x80, 0}))
+	assert.Error(t, jitter.PutRTP(buildRTP(9, 0, 0, false, []byte{1, 2, 3})), "odd L16 payload")
+}
+
+func TestJitterBufferSetDecoder(t *testing.T) {
+	jitter, err := NewJitterBuffer(8000, 20, 40, 200)
+	require.NoError(t, err)
+	defer jitter.Close()
+
+	codec, err := NewG711Codec(false)
+	require.NoError(t, err)
+	defer codec.Close()
+
+	pcm := make([]int16, 160)
+	for i := range pcm {
+		pcm[i] = 4000
+	}
+	encoded := codec.Encode(pcm)
+	want := codec.Decode(encoded)
+
+	decoded := 0
+	jitter.SetDecoder(func(payload []byte) []int16 {
+		decoded++
+		return codec.Decode(payload)
+	})
+	for seq := uint16(0); seq < 5; seq++ {
+		require.NoError(t, jitter.PutRTP(buildRTP(seq, uint32(seq)*160, 0, false, encoded)))
+	}
+	assert.Equal(t, 5, decoded)
+
+	// Playback starts once the minimum delay has built up
+	found := false
+	for i := 0; i < 5 && !found; i++ {
+		frame := jitter.Get(160)
+		require.Len(t, frame, 160)
+		found = assert.ObjectsAreEqual(want, frame)
+	}
+	assert.True(t, found, "decoded PCM never played out")
+}
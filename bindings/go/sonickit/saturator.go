@@ -0,0 +1,114 @@
+package sonickit
+
+import (
+	"math"
+)
+
+// SaturationCurve selects the transfer function used by Saturator.
+type SaturationCurve int
+
+const (
+	// SaturationSoft is a cubic soft clipper.
+	SaturationSoft SaturationCurve = 0
+	// SaturationHard clips at full scale.
+	SaturationHard SaturationCurve = 1
+	// SaturationTube is an asymmetric curve that adds even harmonics.
+	SaturationTube SaturationCurve = 2
+	// SaturationTanh is a hyperbolic tangent curve.
+	SaturationTanh SaturationCurve = 3
+)
+
+// tubeBias is the operating-point offset of the tube curve.
+const tubeBias = 0.2
+
+// Saturator is a waveshaping distortion effect that adds harmonics for
+// warmth or overdrive.
+type Saturator struct {
+	sampleRate int
+	drive      float64
+	curve      SaturationCurve
+}
+
+// NewSaturator creates a new saturator.
+//
+// Parameters:
+//   - sampleRate: Audio sample rate in Hz
+//   - drive: Input gain before shaping (1.0 = unity, higher = more distortion)
+//   - curve: Waveshaping transfer function
+func NewSaturator(sampleRate int, drive float32, curve SaturationCurve) (*Saturator, error) {
+	if err := validate("saturator", checkPositiveRate(sampleRate)); err != nil {
+		return nil, err
+	}
+	s := &Saturator{sampleRate: sampleRate, curve: curve}
+	s.SetDrive(drive)
+	return s, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (s *Saturator) SampleRate() int {
+	return s.sampleRate
+}
+
+// SetDrive sets the input gain before shaping. Values below zero are
+// treated as zero.
+func (s *Saturator) SetDrive(drive float32) {
+	if drive < 0 {
+		drive = 0
+	}
+	s.drive = float64(drive)
+}
+
+// SetCurve selects the waveshaping transfer function.
+func (s *Saturator) SetCurve(curve SaturationCurve) {
+	s.curve = curve
+}
+
+// shape applies the transfer function to x in the -1..1 domain.
+func (s *Saturator) shape(x float64) float64 {
+	switch s.curve {
+	case SaturationHard:
+		return math.Max(-1, math.Min(1, x))
+	case SaturationTube:
+		// A biased tanh clips the halves asymmetrically, adding even
+		// harmonics; subtracting the bias keeps silence at zero.
+		return math.Tanh(x+tubeBias) - math.Tanh(tubeBias)
+	case SaturationTanh:
+		return math.Tanh(x)
+	default:
+		if x >= 1 {
+			return 2.0 / 3
+		}
+		if x <= -1 {
+			return -2.0 / 3
+		}
+		return x - x*x*x/3
+	}
+}
+
+// Process applies saturation to the audio.
+func (s *Saturator) Process(input []int16) []int16 {
+	if len(input) == 0 {
+		return nil
+	}
+	output := make([]int16, len(input))
+	s.ProcessInto(output, input)
+	return output
+}
+
+// ProcessInto applies saturation to src, writing the result to dst without
+// allocating. It returns len(src), or 0 if dst is shorter than src.
+func (s *Saturator) ProcessInto(dst, src []int16) int {
+	if len(src) == 0 || len(dst) < len(src) {
+		return 0
+	}
+	for i, v := range src {
+		dst[i] = clampInt16(s.shape(float64(v)/32768*s.drive) * 32767)
+	}
+	return len(src)
+}
+
+// Close is a no-op; Saturator holds no native resources. It allows a
+// Saturator to be used as a Processor.
+func (s *Saturator) Close() error {
+	return nil
+}
@@ -0,0 +1,75 @@
+package sonickit
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Sine returns n samples of a sine wave at freqHz, starting at zero phase.
+// The amplitude is the peak as a fraction of full scale (0.0-1.0).
+func Sine(sampleRate int, freqHz, amplitude float32, n int) []int16 {
+	if sampleRate <= 0 || n <= 0 {
+		return nil
+	}
+	out := make([]int16, n)
+	step := 2 * math.Pi * float64(freqHz) / float64(sampleRate)
+	peak := float64(amplitude) * 32767
+	for i := range out {
+		out[i] = clampInt16(peak * math.Sin(step*float64(i)))
+	}
+	return out
+}
+
+// Sweep returns n samples of a sine sweeping exponentially from startHz to
+// endHz, spending equal time in each octave. Such a sweep is the usual
+// stimulus for measuring frequency response and distortion. The amplitude
+// is the peak as a fraction of full scale (0.0-1.0). Returns nil unless
+// both frequencies are positive.
+func Sweep(sampleRate, n int, startHz, endHz, amplitude float32) []int16 {
+	if sampleRate <= 0 || n <= 0 || startHz <= 0 || endHz <= 0 {
+		return nil
+	}
+	if startHz == endHz {
+		return Sine(sampleRate, startHz, amplitude, n)
+	}
+	out := make([]int16, n)
+	f0 := float64(startHz)
+	duration := float64(n) / float64(sampleRate)
+	k := math.Log(float64(endHz) / f0)
+	peak := float64(amplitude) * 32767
+	for i := range out {
+		t := float64(i) / float64(sampleRate)
+		phase := 2 * math.Pi * f0 * duration / k * (math.Exp(t*k/duration) - 1)
+		out[i] = clampInt16(peak * math.Sin(phase))
+	}
+	return out
+}
+
+// Impulse returns n samples of silence with a single sample of amplitude at
+// position. Feeding it through a processor yields the impulse response.
+// Returns nil if position is outside the buffer.
+func Impulse(n, position int, amplitude int16) []int16 {
+	if position < 0 || position >= n {
+		return nil
+	}
+	out := make([]int16, n)
+	out[position] = amplitude
+	return out
+}
+
+// WhiteNoise returns n samples of uniformly distributed white noise, with
+// peaks at amplitude as a fraction of full scale (0.0-1.0). The same seed
+// always gives the same samples. For colored noise or a level set in dBFS,
+// use a NoiseGenerator.
+func WhiteNoise(n int, amplitude float32, seed uint64) []int16 {
+	if n <= 0 {
+		return nil
+	}
+	rng := rand.New(rand.NewSource(int64(seed)))
+	out := make([]int16, n)
+	peak := float64(amplitude) * 32767
+	for i := range out {
+		out[i] = clampInt16(peak * (2*rng.Float64() - 1))
+	}
+	return out
+}
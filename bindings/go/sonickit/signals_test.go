@@ -0,0 +1,75 @@
+package sonickit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// zeroCrossings counts sign changes between adjacent samples.
+func zeroCrossings(samples []int16) int {
+	n := 0
+	for i := 1; i < len(samples); i++ {
+		if (samples[i-1] < 0) != (samples[i] < 0) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestSine(t *testing.T) {
+	// One second of a 440Hz sine crosses zero twice per cycle
+	sine := Sine(48000, 440, 0.5, 48000)
+	require.Len(t, sine, 48000)
+	assert.InDelta(t, 880, zeroCrossings(sine), 2)
+
+	var peak int16
+	for _, s := range sine {
+		peak = max(peak, s)
+	}
+	assert.InDelta(t, 16383, peak, 10)
+	assert.Nil(t, Sine(0, 440, 0.5, 100))
+}
+
+func TestSweep(t *testing.T) {
+	// 100Hz-1600Hz over four seconds is one octave per second, so each
+	// second crosses zero twice as often as the one before
+	sweep := Sweep(16000, 64000, 100, 1600, 0.5)
+	require.Len(t, sweep, 64000)
+	prev := zeroCrossings(sweep[:16000])
+	for s := 1; s < 4; s++ {
+		n := zeroCrossings(sweep[s*16000 : (s+1)*16000])
+		assert.InDelta(t, 2.0, float64(n)/float64(prev), 0.1, "second %d", s)
+		prev = n
+	}
+	assert.Nil(t, Sweep(16000, 100, 0, 1000, 0.5))
+	assert.Nil(t, Sweep(16000, 100, 0, 0, 0.5))
+	assert.Nil(t, Sweep(16000, 100, -440, -440, 0.5))
+}
+
+func TestImpulse(t *testing.T) {
+	impulse := Impulse(100, 10, 20000)
+	require.Len(t, impulse, 100)
+	for i, s := range impulse {
+		if i == 10 {
+			assert.Equal(t, int16(20000), s)
+		} else {
+			assert.Zero(t, s)
+		}
+	}
+	assert.Nil(t, Impulse(100, 100, 1))
+}
+
+func TestWhiteNoise(t *testing.T) {
+	noise := WhiteNoise(16000, 0.25, 7)
+	require.Len(t, noise, 16000)
+	for _, s := range noise {
+		assert.LessOrEqual(t, s, int16(8192))
+		assert.GreaterOrEqual(t, s, int16(-8192))
+	}
+	// Uniform noise peaking at 0.25 has an RMS 4.8dB below -12dBFS
+	assert.InDelta(t, -16.8, rmsDbfs(noise), 0.5)
+	assert.Equal(t, noise, WhiteNoise(16000, 0.25, 7))
+	assert.NotEqual(t, noise, WhiteNoise(16000, 0.25, 8))
+}
@@ -21,7 +21,8 @@
 // # Thread Safety
 //
 // Individual processor instances are NOT thread-safe. Use separate instances
-// for different goroutines, or implement your own synchronization.
+// for different goroutines, implement your own synchronization, or wrap a
+// processor with NewSynchronized.
 package sonickit
 
 /*
@@ -38,6 +39,7 @@ import "C"
 import (
 	"fmt"
 	"runtime"
+	"strings"
 )
 
 // Version returns the SonicKit library version string.
@@ -68,6 +70,28 @@ func (v VersionInfo) String() string {
 	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
 }
 
+// BuildFeatures returns the optional capabilities compiled into the native
+// library, such as "speexdsp", "rnnoise", "opus", or "sofa".
+func BuildFeatures() []string {
+	var features []string
+	for _, f := range strings.Split(C.GoString(C.voice_get_features()), ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			features = append(features, f)
+		}
+	}
+	return features
+}
+
+// HasFeature reports whether the named capability was compiled in.
+func HasFeature(name string) bool {
+	for _, f := range BuildFeatures() {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
 // keepAlive prevents the GC from collecting an object while native code is using it.
 func keepAlive(obj interface{}) {
 	runtime.KeepAlive(obj)
@@ -0,0 +1,353 @@
+package sonickit
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// CrossfadeCurve selects the gain curve used by CrossfadeWithCurve.
+type CrossfadeCurve int
+
+const (
+	// CrossfadeLinear fades gains linearly; the gains always sum to 1.
+	CrossfadeLinear CrossfadeCurve = 0
+	// CrossfadeEqualPower keeps the summed power constant, which avoids a
+	// level dip when joining uncorrelated material.
+	CrossfadeEqualPower CrossfadeCurve = 1
+)
+
+// Crossfade joins a and b, linearly crossfading the last overlap samples of
+// a with the first overlap samples of b.
+func Crossfade(a, b []int16, overlap int) []int16 {
+	return CrossfadeWithCurve(a, b, overlap, CrossfadeLinear)
+}
+
+// CrossfadeWithCurve joins a and b with a crossfade of overlap samples using
+// the given curve. The result has len(a)+len(b)-overlap samples; overlap is
+// clamped to the length of the shorter buffer.
+func CrossfadeWithCurve(a, b []int16, overlap int, curve CrossfadeCurve) []int16 {
+	if overlap < 0 {
+		overlap = 0
+	}
+	if overlap > len(a) {
+		overlap = len(a)
+	}
+	if overlap > len(b) {
+		overlap = len(b)
+	}
+
+	head := len(a) - overlap
+	output := make([]int16, len(a)+len(b)-overlap)
+	copy(output, a[:head])
+	for i := 0; i < overlap; i++ {
+		// Position runs from just after 0 to just before 1 so neither
+		// endpoint duplicates a sample outside the overlap.
+		t := (float64(i) + 0.5) / float64(overlap)
+		fadeOut, fadeIn := 1-t, t
+		if curve == CrossfadeEqualPower {
+			fadeOut = math.Cos(t * math.Pi / 2)
+			fadeIn = math.Sin(t * math.Pi / 2)
+		}
+		mixed := float64(a[head+i])*fadeOut + float64(b[i])*fadeIn
+		output[head+i] = clampInt16(mixed)
+	}
+	copy(output[head+overlap:], b[overlap:])
+	return output
+}
+
+// clampInt16 rounds v to the nearest int16, saturating at the type bounds.
+func clampInt16(v float64) int16 {
+	v = math.Round(v)
+	if v > math.MaxInt16 {
+		return math.MaxInt16
+	}
+	if v < math.MinInt16 {
+		return math.MinInt16
+	}
+	return int16(v)
+}
+
+// dcBlockerPole sets the DC blocker cutoff: about 2.5Hz at 16kHz and 7.6Hz
+// at 48kHz.
+const dcBlockerPole = 0.999
+
+// RemoveDC removes DC offset with a one-pole high-pass filter
+// (y[n] = x[n] - x[n-1] + R*y[n-1]). The filter is primed with the first
+// sample so a constant offset does not produce a start-up transient.
+func RemoveDC(samples []int16) []int16 {
+	if len(samples) == 0 {
+		return nil
+	}
+	output := make([]int16, len(samples))
+	prevIn := float64(samples[0])
+	prevOut := 0.0
+	for i, s := range samples {
+		x := float64(s)
+		y := x - prevIn + dcBlockerPole*prevOut
+		prevIn, prevOut = x, y
+		output[i] = clampInt16(y)
+	}
+	return output
+}
+
+// FadeCurve selects how ApplyFade moves between its start and end gains.
+type FadeCurve int
+
+const (
+	// FadeLinear interpolates the gain linearly in amplitude.
+	FadeLinear FadeCurve = 0
+	// FadeLog interpolates the gain linearly in decibels, which sounds
+	// even to the ear.
+	FadeLog FadeCurve = 1
+)
+
+// fadeFloorDb stands in for -Inf when a logarithmic fade has to
+// interpolate in decibels.
+const fadeFloorDb = -100
+
+// dbToGain converts decibels to a linear amplitude factor; -Inf maps to 0.
+func dbToGain(db float64) float64 {
+	return math.Pow(10, db/20)
+}
+
+// ApplyGain returns a copy of samples scaled by db decibels, saturating at
+// the int16 bounds.
+func ApplyGain(samples []int16, db float32) []int16 {
+	if len(samples) == 0 {
+		return nil
+	}
+	output := make([]int16, len(samples))
+	copy(output, samples)
+	ApplyGainInPlace(output, db)
+	return output
+}
+
+// ApplyGainInPlace scales samples by db decibels in place.
+func ApplyGainInPlace(samples []int16, db float32) {
+	gain := dbToGain(float64(db))
+	for i, s := range samples {
+		samples[i] = clampInt16(float64(s) * gain)
+	}
+}
+
+// ApplyFade returns a copy of samples with a gain ramp from startDb at the
+// first sample to endDb at the last. Use math.Inf(-1) for silence.
+func ApplyFade(samples []int16, startDb, endDb float32, curve FadeCurve) []int16 {
+	if len(samples) == 0 {
+		return nil
+	}
+	output := make([]int16, len(samples))
+	copy(output, samples)
+	ApplyFadeInPlace(output, startDb, endDb, curve)
+	return output
+}
+
+// ApplyFadeInPlace applies a gain ramp from startDb to endDb in place.
+func ApplyFadeInPlace(samples []int16, startDb, endDb float32, curve FadeCurve) {
+	n := len(samples)
+	if n == 0 {
+		return
+	}
+	start, end := float64(startDb), float64(endDb)
+	if curve == FadeLog {
+		start = math.Max(start, fadeFloorDb)
+		end = math.Max(end, fadeFloorDb)
+	}
+	startGain, endGain := dbToGain(start), dbToGain(end)
+
+	for i, s := range samples {
+		t := 1.0
+		if n > 1 {
+			t = float64(i) / float64(n-1)
+		}
+		var gain float64
+		if curve == FadeLog {
+			gain = dbToGain(start + (end-start)*t)
+		} else {
+			gain = startGain + (endGain-startGain)*t
+		}
+		samples[i] = clampInt16(float64(s) * gain)
+	}
+}
+
+// PanLaw selects how PanMonoToStereo splits a mono signal between channels.
+type PanLaw int
+
+const (
+	// PanConstantPower keeps the summed power constant across the pan
+	// range; the center position is -3dB per channel.
+	PanConstantPower PanLaw = 0
+	// PanLinear keeps the summed amplitude constant; the center position
+	// is -6dB per channel.
+	PanLinear PanLaw = 1
+)
+
+// PanMonoToStereo pans a mono signal into interleaved stereo. pan runs from
+// -1 (hard left) through 0 (center) to +1 (hard right) and is clamped to
+// that range.
+func PanMonoToStereo(mono []int16, pan float32, law PanLaw) []int16 {
+	if len(mono) == 0 {
+		return nil
+	}
+	p := math.Max(-1, math.Min(1, float64(pan)))
+	t := (p + 1) / 2
+	left, right := 1-t, t
+	if law == PanConstantPower {
+		left = math.Cos(t * math.Pi / 2)
+		right = math.Sin(t * math.Pi / 2)
+	}
+
+	output := make([]int16, len(mono)*2)
+	for i, s := range mono {
+		output[2*i] = clampInt16(float64(s) * left)
+		output[2*i+1] = clampInt16(float64(s) * right)
+	}
+	return output
+}
+
+// Interleave merges planar channels into a single interleaved buffer
+// (L0 R0 L1 R1 ... for stereo). All planes must have the same length.
+func Interleave(channels [][]int16) ([]int16, error) {
+	if len(channels) == 0 {
+		return nil, errors.New("no channels to interleave")
+	}
+	frames := len(channels[0])
+	for ch, plane := range channels {
+		if len(plane) != frames {
+			return nil, fmt.Errorf("channel %d has %d samples, want %d", ch, len(plane), frames)
+		}
+	}
+
+	numChannels := len(channels)
+	output := make([]int16, frames*numChannels)
+	for ch, plane := range channels {
+		for i, s := range plane {
+			output[i*numChannels+ch] = s
+		}
+	}
+	return output, nil
+}
+
+// Deinterleave splits an interleaved buffer into numChannels planar
+// channels. len(interleaved) must be a multiple of numChannels.
+func Deinterleave(interleaved []int16, numChannels int) ([][]int16, error) {
+	if numChannels <= 0 {
+		return nil, errors.New("channel count must be positive")
+	}
+	if len(interleaved)%numChannels != 0 {
+		return nil, fmt.Errorf("%d samples is not a multiple of %d channels", len(interleaved), numChannels)
+	}
+
+	frames := len(interleaved) / numChannels
+	channels := make([][]int16, numChannels)
+	for ch := range channels {
+		plane := make([]int16, frames)
+		for i := range plane {
+			plane[i] = interleaved[i*numChannels+ch]
+		}
+		channels[ch] = plane
+	}
+	return channels, nil
+}
+
+// DownmixToMono mixes interleaved audio with the given number of channels
+// down to mono. Each output sample is the sum of the channel samples
+// multiplied by weights, which has one entry per channel; nil weights
+// average the channels equally. The result saturates at the int16 bounds.
+// A trailing partial frame is dropped. Returns nil if channels is not
+// positive or weights has the wrong length.
+func DownmixToMono(interleaved []int16, channels int, weights []float32) []int16 {
+	if channels <= 0 || (weights != nil && len(weights) != channels) {
+		return nil
+	}
+	gains := make([]float64, channels)
+	for ch := range gains {
+		if weights == nil {
+			gains[ch] = 1 / float64(channels)
+		} else {
+			gains[ch] = float64(weights[ch])
+		}
+	}
+
+	output := make([]int16, len(interleaved)/channels)
+	for i := range output {
+		var sum float64
+		for ch, g := range gains {
+			sum += float64(interleaved[i*channels+ch]) * g
+		}
+		output[i] = clampInt16(sum)
+	}
+	return output
+}
+
+// trimWindowMs is the analysis window TrimSilence uses to measure level.
+const trimWindowMs = 10
+
+// TrimSilence removes leading and trailing audio whose RMS level, measured
+// over 10ms windows, stays below thresholdDb (dBFS). padMs of audio is kept
+// on each side of the detected content so soft onsets and decays are not
+// clipped. Audio that is silent throughout trims to an empty slice. The
+// result is a copy.
+func TrimSilence(samples []int16, sampleRate int, thresholdDb float32, padMs int) []int16 {
+	window := sampleRate * trimWindowMs / 1000
+	if len(samples) == 0 || window <= 0 {
+		return nil
+	}
+
+	first, last := -1, -1
+	for start := 0; start < len(samples); start += window {
+		end := start + window
+		if end > len(samples) {
+			end = len(samples)
+		}
+		if rmsDbfs(samples[start:end]) >= thresholdDb {
+			if first < 0 {
+				first = start
+			}
+			last = end
+		}
+	}
+	if first < 0 {
+		return []int16{}
+	}
+
+	pad := sampleRate * padMs / 1000
+	first -= pad
+	if first < 0 {
+		first = 0
+	}
+	last += pad
+	if last > len(samples) {
+		last = len(samples)
+	}
+	output := make([]int16, last-first)
+	copy(output, samples[first:last])
+	return output
+}
+
+// NullTest compares two equal-length buffers sample by sample, as when
+// checking that a refactor leaves DSP output unchanged. It returns the
+// largest absolute sample difference and the RMS level of the difference
+// signal in dBFS, which is -Inf for identical buffers.
+func NullTest(a, b []int16) (maxDiff int, rmsDiffDb float32, err error) {
+	if len(a) != len(b) {
+		return 0, 0, fmt.Errorf("null test length mismatch: %d vs %d samples", len(a), len(b))
+	}
+	var sum float64
+	for i := range a {
+		d := int(a[i]) - int(b[i])
+		if d < 0 {
+			d = -d
+		}
+		if d > maxDiff {
+			maxDiff = d
+		}
+		sum += float64(d) * float64(d)
+	}
+	if sum == 0 {
+		return 0, float32(math.Inf(-1)), nil
+	}
+	rms := math.Sqrt(sum/float64(len(a))) / 32768
+	return maxDiff, float32(20 * math.Log10(rms)), nil
+}
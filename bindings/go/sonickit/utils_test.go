@@ -0,0 +1,222 @@
+package sonickit
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrossfade(t *testing.T) {
+	a := make([]int16, 100)
+	b := make([]int16, 100)
+	for i := range a {
+		a[i] = 1000
+		b[i] = 9000
+	}
+
+	output := Crossfade(a, b, 40)
+	require.Len(t, output, 160)
+	assert.Equal(t, int16(1000), output[0])
+	assert.Equal(t, int16(9000), output[159])
+
+	// The overlap region ramps monotonically from a to b
+	for i := 60; i < 100; i++ {
+		assert.GreaterOrEqual(t, output[i], output[i-1])
+	}
+
+	// Equal-power gains sum above unity mid-fade, bulging on correlated input
+	output = CrossfadeWithCurve(a, b, 40, CrossfadeEqualPower)
+	require.Len(t, output, 160)
+	assert.Greater(t, output[99], int16(9000))
+
+	// Overlap larger than the inputs is clamped
+	assert.Len(t, Crossfade(a[:10], b[:20], 50), 20)
+}
+
+func TestRemoveDC(t *testing.T) {
+	// A 100Hz tone riding on a +5000 offset
+	input := make([]int16, 16000)
+	for i := range input {
+		input[i] = int16(5000 + 3000*math.Sin(2*math.Pi*100*float64(i)/16000))
+	}
+	output := RemoveDC(input)
+	require.Len(t, output, len(input))
+
+	var mean float64
+	for _, s := range output[8000:] {
+		mean += float64(s)
+	}
+	mean /= 8000
+	assert.InDelta(t, 0, mean, 100)
+
+	// A pure offset is removed entirely
+	for i := range input {
+		input[i] = -2000
+	}
+	for _, s := range RemoveDC(input) {
+		assert.Equal(t, int16(0), s)
+	}
+}
+
+func TestApplyGain(t *testing.T) {
+	input := []int16{10000, -10000, 20000, 30000}
+	output := ApplyGain(input, -6)
+	require.Len(t, output, len(input))
+	assert.InDelta(t, 5012, output[0], 2)
+	assert.InDelta(t, -5012, output[1], 2)
+	assert.Equal(t, int16(10000), input[0], "input must not be modified")
+
+	// Boosting saturates rather than wrapping
+	loud := ApplyGain(input, 12)
+	assert.Equal(t, int16(math.MaxInt16), loud[3])
+	assert.Equal(t, int16(math.MinInt16), loud[1])
+
+	ApplyGainInPlace(input, -6)
+	assert.Equal(t, output, input)
+}
+
+func TestApplyFade(t *testing.T) {
+	input := make([]int16, 1000)
+	for i := range input {
+		input[i] = 20000
+	}
+
+	for _, curve := range []FadeCurve{FadeLinear, FadeLog} {
+		output := ApplyFade(input, float32(math.Inf(-1)), 0, curve)
+		require.Len(t, output, len(input))
+		assert.InDelta(t, 0, output[0], 1)
+		assert.Equal(t, int16(20000), output[len(output)-1])
+		for i := 1; i < len(output); i++ {
+			assert.GreaterOrEqual(t, output[i], output[i-1])
+		}
+	}
+
+	// Linear amplitude is halfway at the midpoint; the log curve is far lower
+	linear := ApplyFade(input, float32(math.Inf(-1)), 0, FadeLinear)
+	logFade := ApplyFade(input, float32(math.Inf(-1)), 0, FadeLog)
+	assert.InDelta(t, 10000, linear[500], 50)
+	assert.Less(t, logFade[500], linear[500])
+
+	ApplyFadeInPlace(input, 0, -6, FadeLinear)
+	assert.Equal(t, int16(20000), input[0])
+	assert.InDelta(t, 10024, input[len(input)-1], 2)
+}
+
+func TestPanMonoToStereo(t *testing.T) {
+	mono := []int16{20000, -20000, 10000}
+
+	center := PanMonoToStereo(mono, 0, PanConstantPower)
+	require.Len(t, center, 2*len(mono))
+	for i, s := range mono {
+		want := float64(s) * math.Pow(10, -3.0103/20)
+		assert.InDelta(t, want, center[2*i], 1)
+		assert.InDelta(t, want, center[2*i+1], 1)
+	}
+
+	linear := PanMonoToStereo(mono, 0, PanLinear)
+	assert.Equal(t, int16(10000), linear[0])
+	assert.Equal(t, int16(10000), linear[1])
+
+	hardLeft := PanMonoToStereo(mono, -1, PanConstantPower)
+	assert.Equal(t, int16(20000), hardLeft[0])
+	assert.Equal(t, int16(0), hardLeft[1])
+
+	// Out-of-range pan is clamped to hard right
+	hardRight := PanMonoToStereo(mono, 2, PanLinear)
+	assert.Equal(t, int16(0), hardRight[0])
+	assert.Equal(t, int16(20000), hardRight[1])
+
+	assert.Nil(t, PanMonoToStereo(nil, 0, PanLinear))
+}
+
+func TestInterleave(t *testing.T) {
+	left := []int16{1, 2, 3, 4}
+	right := []int16{-1, -2, -3, -4}
+
+	interleaved, err := Interleave([][]int16{left, right})
+	require.NoError(t, err)
+	assert.Equal(t, []int16{1, -1, 2, -2, 3, -3, 4, -4}, interleaved)
+
+	planes, err := Deinterleave(interleaved, 2)
+	require.NoError(t, err)
+	require.Len(t, planes, 2)
+	assert.Equal(t, left, planes[0])
+	assert.Equal(t, right, planes[1])
+
+	_, err = Interleave([][]int16{left, right[:3]})
+	assert.Error(t, err)
+	_, err = Interleave(nil)
+	assert.Error(t, err)
+
+	_, err = Deinterleave(interleaved[:7], 2)
+	assert.Error(t, err)
+	_, err = Deinterleave(interleaved, 0)
+	assert.Error(t, err)
+}
+
+func TestDownmixToMono(t *testing.T) {
+	stereo := []int16{100, 300, -200, -400, 32767, 32767, 1, 2}
+	assert.Equal(t, []int16{200, -300, 32767, 2}, DownmixToMono(stereo, 2, nil))
+
+	// Weights summing above 1 saturate
+	assert.Equal(t, []int16{400, -600, 32767, 3}, DownmixToMono(stereo, 2, []float32{1, 1}))
+	assert.Equal(t, []int16{100, -200, 32767, 1}, DownmixToMono(stereo, 2, []float32{1, 0}))
+
+	assert.Nil(t, DownmixToMono(stereo, 2, []float32{1}))
+	assert.Nil(t, DownmixToMono(stereo, 0, nil))
+}
+
+func TestTrimSilence(t *testing.T) {
+	const rate = 16000
+	// 500ms silence, 300ms tone, 700ms silence
+	samples := make([]int16, rate*3/2)
+	toneStart, toneEnd := rate/2, rate*8/10
+	for i := toneStart; i < toneEnd; i++ {
+		samples[i] = int16(8000 * math.Sin(2*math.Pi*300*float64(i)/rate))
+	}
+
+	trimmed := TrimSilence(samples, rate, -50, 50)
+	assert.Less(t, len(trimmed), len(samples))
+	// The tone plus at most 50ms padding and one window of rounding per side
+	assert.InDelta(t, (toneEnd-toneStart)+2*rate*50/1000, len(trimmed), float64(2*rate/100))
+
+	// Every tone sample survives
+	var kept, original float64
+	for _, s := range trimmed {
+		kept += math.Abs(float64(s))
+	}
+	for _, s := range samples {
+		original += math.Abs(float64(s))
+	}
+	assert.Equal(t, original, kept)
+
+	assert.Empty(t, TrimSilence(make([]int16, rate), rate, -50, 50))
+	assert.Len(t, TrimSilence(samples[toneStart:toneEnd], rate, -50, 50), toneEnd-toneStart)
+}
+
+func TestNullTest(t *testing.T) {
+	a := make([]int16, 1000)
+	for i := range a {
+		a[i] = int16(10000 * math.Sin(float64(i)/10))
+	}
+	b := append([]int16(nil), a...)
+
+	maxDiff, rmsDb, err := NullTest(a, b)
+	require.NoError(t, err)
+	assert.Equal(t, 0, maxDiff)
+	assert.Less(t, rmsDb, float32(-150))
+
+	// A single-LSB difference everywhere is about -90dBFS
+	for i := range b {
+		b[i]++
+	}
+	maxDiff, rmsDb, err = NullTest(a, b)
+	require.NoError(t, err)
+	assert.Equal(t, 1, maxDiff)
+	assert.InDelta(t, -90.3, rmsDb, 0.1)
+
+	_, _, err = NullTest(a, b[:10])
+	assert.Error(t, err)
+}
@@ -0,0 +1,141 @@
+package sonickit
+
+// VoicePreset selects a VoiceChanger persona.
+type VoicePreset int
+
+const (
+	// VoiceNormal leaves the voice unchanged apart from the latency.
+	VoiceNormal VoicePreset = 0
+	// VoiceRobot ring-modulates the voice for a metallic, synthetic sound.
+	VoiceRobot VoicePreset = 1
+	// VoiceMonster drops the pitch and formants for a huge, growling voice.
+	VoiceMonster VoicePreset = 2
+	// VoiceChild raises the pitch and formants and thins out the lows.
+	VoiceChild VoicePreset = 3
+	// VoiceDeep lowers the pitch but keeps the formants, so the speaker
+	// still sounds natural, just deeper.
+	VoiceDeep VoicePreset = 4
+)
+
+// voiceSettings is the processing chain configuration for one preset.
+type voiceSettings struct {
+	semitones      float32
+	preserve       bool    // keep formants in place while shifting
+	low, mid, high float32 // EQ gains in dB
+	ringHz         float32 // ring modulator carrier
+	ringMix        float32 // ring modulator depth, 0 for none
+}
+
+// voicePresets maps each preset to its settings.
+var voicePresets = map[VoicePreset]voiceSettings{
+	VoiceNormal:  {},
+	VoiceRobot:   {mid: 4, ringHz: 50, ringMix: 0.8},
+	VoiceMonster: {semitones: -7, low: 6, high: -6},
+	VoiceChild:   {semitones: 5, low: -6, high: 3},
+	VoiceDeep:    {semitones: -4, preserve: true, low: 3},
+}
+
+// Equalizer bands used by VoiceChanger.
+const (
+	voiceEqLowHz  = 150
+	voiceEqMidHz  = 1000
+	voiceEqHighHz = 4000
+	voiceEqQ      = 0.7
+)
+
+// VoiceChanger turns a voice into a preset persona with a single call. It
+// chains a PitchShifter, a three-band Equalizer and a RingModulator.
+type VoiceChanger struct {
+	sampleRate int
+	shifter    *PitchShifter
+	eq         *Equalizer
+	ring       *RingModulator
+	preset     VoicePreset
+}
+
+// NewVoiceChanger creates a new voice changer set to VoiceNormal.
+func NewVoiceChanger(sampleRate int) (*VoiceChanger, error) {
+	shifter, err := NewPitchShifter(sampleRate, 0)
+	if err != nil {
+		return nil, err
+	}
+	eq, err := NewEqualizer(sampleRate, 3)
+	if err != nil {
+		shifter.Close()
+		return nil, err
+	}
+	ring, err := NewRingModulator(sampleRate, 0)
+	if err != nil {
+		shifter.Close()
+		eq.Close()
+		return nil, err
+	}
+	v := &VoiceChanger{sampleRate: sampleRate, shifter: shifter, eq: eq, ring: ring}
+	v.SetPreset(VoiceNormal)
+	return v, nil
+}
+
+// SampleRate returns the sample rate in Hz.
+func (v *VoiceChanger) SampleRate() int {
+	return v.sampleRate
+}
+
+// SetPreset switches to a preset. It may be called between Process calls
+// to change persona mid-stream. Unknown presets are ignored.
+func (v *VoiceChanger) SetPreset(preset VoicePreset) {
+	s, ok := voicePresets[preset]
+	if !ok {
+		return
+	}
+	v.preset = preset
+	v.shifter.SetPitch(s.semitones)
+	v.shifter.SetFormantPreserve(s.preserve)
+	v.eq.SetBand(0, voiceEqLowHz, s.low, voiceEqQ)
+	v.eq.SetBand(1, voiceEqMidHz, s.mid, voiceEqQ)
+	v.eq.SetBand(2, voiceEqHighHz, s.high, voiceEqQ)
+	v.ring.SetFrequency(s.ringHz)
+	v.ring.SetMix(s.ringMix)
+}
+
+// Preset returns the current preset.
+func (v *VoiceChanger) Preset() VoicePreset {
+	return v.preset
+}
+
+// Process applies the voice change to the audio.
+//
+// Like PitchShifter.Process, the output may be shorter or longer than the
+// input; call Flush at end of stream to drain it.
+func (v *VoiceChanger) Process(input []int16) []int16 {
+	if v.shifter.handle == nil || len(input) == 0 {
+		return nil
+	}
+	return v.finish(v.shifter.Process(input))
+}
+
+// Flush drains the pitch shifter at end of stream. See PitchShifter.Flush.
+func (v *VoiceChanger) Flush() []int16 {
+	return v.finish(v.shifter.Flush())
+}
+
+// finish runs pitch-shifted audio through the equalizer and ring modulator.
+func (v *VoiceChanger) finish(shifted []int16) []int16 {
+	if len(shifted) == 0 {
+		return nil
+	}
+	v.eq.ProcessInPlace(shifted)
+	v.ring.ProcessInto(shifted, shifted)
+	return shifted
+}
+
+// Latency returns the processing delay in samples.
+func (v *VoiceChanger) Latency() int {
+	return v.shifter.Latency() + v.eq.Latency()
+}
+
+// Close releases the pitch shifter, equalizer and ring modulator.
+func (v *VoiceChanger) Close() error {
+	v.eq.Close()
+	v.ring.Close()
+	return v.shifter.Close()
+}
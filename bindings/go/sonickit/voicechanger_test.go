@@ -0,0 +1,35 @@
+package sonickit
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVoiceChangerRobot(t *testing.T) {
+	const sampleRate = 16000
+	changer, err := NewVoiceChanger(sampleRate)
+	require.NoError(t, err)
+	defer changer.Close()
+	changer.SetPreset(VoiceRobot)
+	assert.Equal(t, VoiceRobot, changer.Preset())
+
+	input := make([]int16, 2*sampleRate)
+	for i := range input {
+		input[i] = int16(8000 * math.Sin(2*math.Pi*300*float64(i)/sampleRate))
+	}
+	var output []int16
+	for start := 0; start < len(input); start += 320 {
+		output = append(output, changer.Process(input[start:start+320])...)
+	}
+	output = append(output, changer.Flush()...)
+	require.Len(t, output, len(input)+changer.Latency())
+
+	// Ring modulation at 50Hz moves energy into 250Hz and 350Hz sidebands
+	window := output[changer.Latency() : changer.Latency()+sampleRate]
+	in := input[:sampleRate]
+	assert.Greater(t, harmonicLevel(window, sampleRate, 350), 10*harmonicLevel(in, sampleRate, 350))
+	assert.Less(t, harmonicLevel(window, sampleRate, 300), harmonicLevel(in, sampleRate, 300))
+}
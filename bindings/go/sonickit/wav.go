@@ -0,0 +1,236 @@
+package sonickit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// WAVFrameReader streams 16-bit PCM WAV files as fixed-size frames without
+// loading the whole file into memory.
+type WAVFrameReader struct {
+	file       *os.File
+	reader     *bufio.Reader
+	frameSize  int
+	sampleRate int
+	channels   int
+	remaining  int64 // bytes left in the data chunk
+	lastValid  int
+	buf        []byte
+}
+
+// NewWAVFrameReader opens a WAV file for frame-by-frame reading.
+//
+// Parameters:
+//   - path: Path to a 16-bit PCM WAV file
+//   - frameSize: Number of samples per channel in each frame
+func NewWAVFrameReader(path string, frameSize int) (*WAVFrameReader, error) {
+	if frameSize <= 0 {
+		return nil, errors.New("frame size must be positive")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &WAVFrameReader{
+		file:      f,
+		reader:    bufio.NewReader(f),
+		frameSize: frameSize,
+	}
+	if err := r.readHeader(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// readHeader parses the RIFF chunks up to the start of the data chunk.
+func (r *WAVFrameReader) readHeader() error {
+	sampleRate, channels, size, err := readWAVHeader(r.reader)
+	if err != nil {
+		return err
+	}
+	r.sampleRate = sampleRate
+	r.channels = channels
+	r.remaining = size
+	r.buf = make([]byte, r.frameSize*r.channels*2)
+	return nil
+}
+
+// readWAVHeader parses the RIFF chunks of a 16-bit PCM WAV stream up to the
+// start of the data chunk, returning the format and the data chunk size.
+func readWAVHeader(reader *bufio.Reader) (sampleRate, channels int, dataSize int64, err error) {
+	var riff [12]byte
+	if _, err := io.ReadFull(reader, riff[:]); err != nil {
+		return 0, 0, 0, fmt.Errorf("read WAV header: %w", err)
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return 0, 0, 0, errors.New("not a RIFF/WAVE file")
+	}
+
+	haveFmt := false
+	for {
+		var hdr [8]byte
+		if _, err := io.ReadFull(reader, hdr[:]); err != nil {
+			return 0, 0, 0, fmt.Errorf("read WAV chunk: %w", err)
+		}
+		id := string(hdr[0:4])
+		size := int64(binary.LittleEndian.Uint32(hdr[4:8]))
+
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return 0, 0, 0, errors.New("WAV fmt chunk too short")
+			}
+			fmtChunk := make([]byte, size)
+			if _, err := io.ReadFull(reader, fmtChunk); err != nil {
+				return 0, 0, 0, fmt.Errorf("read WAV fmt chunk: %w", err)
+			}
+			format := binary.LittleEndian.Uint16(fmtChunk[0:2])
+			bits := binary.LittleEndian.Uint16(fmtChunk[14:16])
+			if format != 1 || bits != 16 {
+				return 0, 0, 0, fmt.Errorf("unsupported WAV format %d with %d bits (want 16-bit PCM)", format, bits)
+			}
+			channels = int(binary.LittleEndian.Uint16(fmtChunk[2:4]))
+			sampleRate = int(binary.LittleEndian.Uint32(fmtChunk[4:8]))
+			if channels <= 0 {
+				return 0, 0, 0, errors.New("WAV file has no channels")
+			}
+			if size&1 == 1 {
+				if _, err := reader.Discard(1); err != nil {
+					return 0, 0, 0, fmt.Errorf("skip WAV pad byte: %w", err)
+				}
+			}
+			haveFmt = true
+		case "data":
+			if !haveFmt {
+				return 0, 0, 0, errors.New("WAV data chunk precedes fmt chunk")
+			}
+			return sampleRate, channels, size, nil
+		default:
+			// Skip unknown chunks, honoring the RIFF pad byte
+			if _, err := reader.Discard(int(size + size&1)); err != nil {
+				return 0, 0, 0, fmt.Errorf("skip WAV chunk %q: %w", id, err)
+			}
+		}
+	}
+}
+
+// SampleRate returns the sample rate of the file in Hz.
+func (r *WAVFrameReader) SampleRate() int {
+	return r.sampleRate
+}
+
+// Channels returns the number of interleaved channels in the file.
+func (r *WAVFrameReader) Channels() int {
+	return r.channels
+}
+
+// Next returns the next frame of frameSize*Channels() interleaved samples.
+//
+// The final partial frame is zero-padded to full length; LastFrameLen
+// reports how many of its samples came from the file. Next returns io.EOF
+// once all samples have been read.
+func (r *WAVFrameReader) Next() ([]int16, error) {
+	if r.file == nil {
+		return nil, errors.New("WAV reader is closed")
+	}
+	if r.remaining < 2 {
+		return nil, io.EOF
+	}
+	want := int64(len(r.buf))
+	if want > r.remaining {
+		want = r.remaining &^ 1
+	}
+	n, err := io.ReadFull(r.reader, r.buf[:want])
+	r.remaining -= int64(n)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		// Truncated file: treat what we got as the final frame
+		r.remaining = 0
+	}
+
+	frame := make([]int16, r.frameSize*r.channels)
+	r.lastValid = n / 2
+	for i := 0; i < r.lastValid; i++ {
+		frame[i] = int16(binary.LittleEndian.Uint16(r.buf[i*2:]))
+	}
+	return frame, nil
+}
+
+// LastFrameLen returns the number of real (non-padding) interleaved
+// samples, counting every channel, in the frame most recently returned by
+// Next, so frame[:LastFrameLen()] holds the audio read from the file.
+// Divide by Channels() for the number of samples per channel.
+func (r *WAVFrameReader) LastFrameLen() int {
+	return r.lastValid
+}
+
+// Close closes the underlying file.
+func (r *WAVFrameReader) Close() error {
+	if r.file != nil {
+		err := r.file.Close()
+		r.file = nil
+		return err
+	}
+	return nil
+}
+
+// wavHeaderSize is the length of the canonical header EncodeWAV writes.
+const wavHeaderSize = 44
+
+// EncodeWAV returns samples as a 16-bit PCM WAV file in memory, for
+// serving audio over HTTP or storing it without touching the disk. Returns
+// nil if sampleRate or channels is not positive.
+func EncodeWAV(samples []int16, sampleRate, channels int) []byte {
+	if sampleRate <= 0 || channels <= 0 {
+		return nil
+	}
+	dataLen := len(samples) * 2
+	buf := make([]byte, wavHeaderSize+dataLen)
+	copy(buf[0:], "RIFF")
+	binary.LittleEndian.PutUint32(buf[4:], uint32(wavHeaderSize-8+dataLen))
+	copy(buf[8:], "WAVE")
+	copy(buf[12:], "fmt ")
+	binary.LittleEndian.PutUint32(buf[16:], 16)
+	binary.LittleEndian.PutUint16(buf[20:], 1)
+	binary.LittleEndian.PutUint16(buf[22:], uint16(channels))
+	binary.LittleEndian.PutUint32(buf[24:], uint32(sampleRate))
+	binary.LittleEndian.PutUint32(buf[28:], uint32(sampleRate*channels*2))
+	binary.LittleEndian.PutUint16(buf[32:], uint16(channels*2))
+	binary.LittleEndian.PutUint16(buf[34:], 16)
+	copy(buf[36:], "data")
+	binary.LittleEndian.PutUint32(buf[40:], uint32(dataLen))
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(buf[wavHeaderSize+i*2:], uint16(s))
+	}
+	return buf
+}
+
+// DecodeWAV parses a 16-bit PCM WAV file held in memory, returning its
+// interleaved samples and format. It accepts the same files as
+// WAVFrameReader, and likewise returns what it can of a truncated data
+// chunk rather than failing.
+func DecodeWAV(data []byte) (samples []int16, sampleRate, channels int, err error) {
+	reader := bufio.NewReader(bytes.NewReader(data))
+	sampleRate, channels, size, err := readWAVHeader(reader)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	body := make([]byte, min(size, int64(len(data)))&^1)
+	n, err := io.ReadFull(reader, body)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, 0, 0, err
+	}
+	samples = make([]int16, n/2)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(body[i*2:]))
+	}
+	return samples, sampleRate, channels, nil
+}
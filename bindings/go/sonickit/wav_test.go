@@ -0,0 +1,120 @@
+package sonickit
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestWAV writes a minimal 16-bit PCM WAV file.
+func writeTestWAV(t *testing.T, samples []int16, sampleRate, channels int) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.wav")
+	dataLen := len(samples) * 2
+	hdr := make([]byte, 44)
+	copy(hdr[0:], "RIFF")
+	binary.LittleEndian.PutUint32(hdr[4:], uint32(36+dataLen))
+	copy(hdr[8:], "WAVE")
+	copy(hdr[12:], "fmt ")
+	binary.LittleEndian.PutUint32(hdr[16:], 16)
+	binary.LittleEndian.PutUint16(hdr[20:], 1)
+	binary.LittleEndian.PutUint16(hdr[22:], uint16(channels))
+	binary.LittleEndian.PutUint32(hdr[24:], uint32(sampleRate))
+	binary.LittleEndian.PutUint32(hdr[28:], uint32(sampleRate*channels*2))
+	binary.LittleEndian.PutUint16(hdr[32:], uint16(channels*2))
+	binary.LittleEndian.PutUint16(hdr[34:], 16)
+	copy(hdr[36:], "data")
+	binary.LittleEndian.PutUint32(hdr[40:], uint32(dataLen))
+	data := make([]byte, dataLen)
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(data[i*2:], uint16(s))
+	}
+	require.NoError(t, os.WriteFile(path, append(hdr, data...), 0o644))
+	return path
+}
+
+func TestWAVFrameReader(t *testing.T) {
+	// 1000 samples in 160-sample frames: 6 full frames + 40 samples
+	samples := make([]int16, 1000)
+	for i := range samples {
+		samples[i] = int16(i + 1)
+	}
+	path := writeTestWAV(t, samples, 16000, 1)
+
+	reader, err := NewWAVFrameReader(path, 160)
+	require.NoError(t, err)
+	require.NotNil(t, reader)
+	defer reader.Close()
+
+	assert.Equal(t, 16000, reader.SampleRate())
+	assert.Equal(t, 1, reader.Channels())
+
+	var frames [][]int16
+	for {
+		frame, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		assert.Len(t, frame, 160)
+		frames = append(frames, frame)
+	}
+	require.Len(t, frames, 7)
+
+	// Final frame holds 40 real samples followed by zero padding
+	last := frames[6]
+	assert.Equal(t, 40, reader.LastFrameLen())
+	assert.Equal(t, int16(961), last[0])
+	assert.Equal(t, int16(1000), last[39])
+	for _, s := range last[40:] {
+		assert.Equal(t, int16(0), s)
+	}
+}
+
+func TestEncodeDecodeWAV(t *testing.T) {
+	samples := Sine(16000, 440, 0.5, 1100)
+	data := EncodeWAV(samples, 16000, 2)
+
+	// Byte for byte the same as a WAV file on disk
+	file, err := os.ReadFile(writeTestWAV(t, samples, 16000, 2))
+	require.NoError(t, err)
+	assert.Equal(t, file, data)
+
+	decoded, sampleRate, channels, err := DecodeWAV(data)
+	require.NoError(t, err)
+	assert.Equal(t, samples, decoded)
+	assert.Equal(t, 16000, sampleRate)
+	assert.Equal(t, 2, channels)
+
+	// The same samples WAVFrameReader reads from the file
+	path := filepath.Join(t.TempDir(), "encoded.wav")
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+	reader, err := NewWAVFrameReader(path, 250)
+	require.NoError(t, err)
+	defer reader.Close()
+	var read []int16
+	for {
+		frame, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		read = append(read, frame[:reader.LastFrameLen()]...)
+	}
+	// The last frame holds 50 of its 250 samples per channel
+	assert.Equal(t, 100, reader.LastFrameLen())
+	assert.Equal(t, read, decoded)
+
+	// A truncated data chunk yields the samples that are present
+	decoded, _, _, err = DecodeWAV(data[:len(data)-101])
+	require.NoError(t, err)
+	assert.Equal(t, samples[:len(samples)-51], decoded)
+
+	_, _, _, err = DecodeWAV([]byte("not a wav file"))
+	assert.Error(t, err)
+}
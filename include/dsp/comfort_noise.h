@@ -148,6 +148,12 @@ VOICE_API voice_error_t voice_cng_decode_sid(
  */
 VOICE_API void voice_cng_reset(voice_cng_t *cng);
 
+/**
+ * @brief Seed the noise generator shared by all CNG instances
+ * @param seed Generator seed; the same seed reproduces the same noise
+ */
+VOICE_API void voice_cng_set_seed(uint32_t seed);
+
 #ifdef __cplusplus
 }
 #endif
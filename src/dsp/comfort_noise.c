@@ -21,6 +21,10 @@ static uint32_t cng_rand(void) {
     return g_cng_seed;
 }
 
+void voice_cng_set_seed(uint32_t seed) {
+    g_cng_seed = seed;
+}
+
 static float cng_randf(void) {
     return ((float)(cng_rand() & 0x7FFFFFFF) / (float)0x7FFFFFFF) * 2.0f - 1.0f;
 }
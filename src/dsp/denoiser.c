@@ -61,12 +61,14 @@ void voice_denoiser_config_init(voice_denoiser_config_t *config)
 
 voice_denoiser_t *voice_denoiser_create(const voice_denoiser_config_t *config)
 {
-    if (!config) {
+    if (!config || config->sample_rate == 0 || config->frame_size == 0) {
+        voice_set_last_error(VOICE_ERROR_INVALID_PARAM);
         return NULL;
     }
 
     voice_denoiser_t *d = (voice_denoiser_t *)calloc(1, sizeof(voice_denoiser_t));
     if (!d) {
+        voice_set_last_error(VOICE_ERROR_OUT_OF_MEMORY);
         return NULL;
     }
 
@@ -77,6 +79,7 @@ voice_denoiser_t *voice_denoiser_create(const voice_denoiser_config_t *config)
     d->float_buffer = (float *)calloc(d->float_buffer_size, sizeof(float));
     if (!d->float_buffer) {
         free(d);
+        voice_set_last_error(VOICE_ERROR_OUT_OF_MEMORY);
         return NULL;
     }
 